@@ -5,6 +5,8 @@ import (
 )
 
 // AnimeMonitorSpec defines the desired state of AnimeMonitor
+// +kubebuilder:validation:XValidation:rule="!has(self.scoringMode) || self.scoringMode != 'Expression' || has(self.scoringExpression)",message="scoringExpression is required when scoringMode is Expression"
+// +kubebuilder:validation:XValidation:rule="!has(self.anomalyDetection) || (has(self.anomalyDetection.minSamples) ? self.anomalyDetection.minSamples : 6) <= (has(self.historySize) ? self.historySize : 24)",message="anomalyDetection.minSamples must not exceed historySize, or anomaly detection can never start"
 type AnimeMonitorSpec struct {
 	// AnimeID is the MyAnimeList ID of a specific anime to monitor (optional)
 	// If not set, monitors overall MAL activity
@@ -36,8 +38,359 @@ type AnimeMonitorSpec struct {
 	// WebhookURL for sending activity notifications
 	// +optional
 	WebhookURL string `json:"webhookUrl,omitempty"`
+
+	// HistorySize is the number of recent samples kept in status.history
+	// Kept small so the object stays light in etcd; set to 0 to disable
+	// +kubebuilder:default=24
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	HistorySize *int `json:"historySize,omitempty"`
+
+	// ScoringMode selects how the activity score is computed
+	// Absolute scores lifetime totals; Momentum scores the rate of change
+	// between polls (new members and watchers per hour, score movement);
+	// Expression evaluates ScoringExpression
+	// +kubebuilder:default=Absolute
+	// +optional
+	ScoringMode ScoringMode `json:"scoringMode,omitempty"`
+
+	// ScoringExpression is a CEL expression over metrics, delta and history
+	// that computes the activity score in Expression mode,
+	// e.g. "metrics.watching * 5 + delta.members / 10"
+	// +optional
+	ScoringExpression string `json:"scoringExpression,omitempty"`
+
+	// ScoringWeights tunes how Absolute scoring weighs each metric for a
+	// specific anime; unset uses the defaults
+	// +optional
+	ScoringWeights *ScoringWeights `json:"scoringWeights,omitempty"`
+
+	// AnomalyDetection flags metrics that deviate sharply from their rolling
+	// mean over status.history; unset disables it
+	// +optional
+	AnomalyDetection *AnomalyDetectionSpec `json:"anomalyDetection,omitempty"`
+
+	// ThresholdMode selects how level thresholds are chosen
+	// Manual uses the thresholds above; Auto derives them from percentiles
+	// of the monitor's own activity scores in the operator's history store,
+	// and uses the manual thresholds when the operator runs without one
+	// +kubebuilder:default=Manual
+	// +optional
+	ThresholdMode ThresholdMode `json:"thresholdMode,omitempty"`
+
+	// AutoThresholds configures threshold calibration in Auto mode
+	// +optional
+	AutoThresholds *AutoThresholdsSpec `json:"autoThresholds,omitempty"`
+
+	// LevelLadder replaces the built-in levels with custom named levels,
+	// ordered by ascending threshold. Each step maps onto one of the
+	// built-in levels, which is still reported in status.activityLevel.
+	// The ladder's thresholds replace the threshold settings above
+	// +kubebuilder:validation:MaxItems=12
+	// +optional
+	LevelLadder []LevelStep `json:"levelLadder,omitempty"`
+
+	// MessageTemplatesConfigMap names a ConfigMap in the monitor's namespace
+	// whose keys (Low, Medium, High, Critical, or <locale>.<level> such as
+	// ja.High) are Go text/template templates replacing or adding to the
+	// built-in WeebcastStatus messages. Overrides the operator's
+	// --message-templates ConfigMap
+	// +optional
+	MessageTemplatesConfigMap string `json:"messageTemplatesConfigMap,omitempty"`
+
+	// Outlook projects the activity score forward and reports the expected
+	// level per time bucket in status.outlook
+	// +optional
+	Outlook *OutlookSpec `json:"outlook,omitempty"`
+
+	// LevelTransitions damps flapping between activity levels when the
+	// score hovers near a threshold
+	// +optional
+	LevelTransitions *LevelTransitionSpec `json:"levelTransitions,omitempty"`
+}
+
+// LevelStep is one rung of a custom activity level ladder
+type LevelStep struct {
+	// Name is the level's display name, e.g. "Gale"
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Threshold is the lowest activity score at this level
+	// +kubebuilder:validation:Minimum=0
+	Threshold int `json:"threshold"`
+
+	// Icon is shown alongside the name, e.g. "🌬️"
+	// +optional
+	Icon string `json:"icon,omitempty"`
+
+	// MapsTo is the built-in level reported for this step to consumers that
+	// only understand Low, Medium, High and Critical
+	MapsTo ActivityLevel `json:"mapsTo"`
+}
+
+// LevelTransitionSpec configures hysteresis and dwell time for level changes
+type LevelTransitionSpec struct {
+	// HysteresisPercent is how far below a level's threshold, as a
+	// percentage of the threshold, the score must fall before the level is
+	// left. Rising into a level still only requires reaching its threshold
+	// +kubebuilder:default=10
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=50
+	// +optional
+	HysteresisPercent int `json:"hysteresisPercent,omitempty"`
+
+	// MinDwell is how long a new level must persist before it is committed
+	// +optional
+	MinDwell *metav1.Duration `json:"minDwell,omitempty"`
+}
+
+// OutlookSeasonality selects whether the outlook models a weekly cycle
+// +kubebuilder:validation:Enum=Auto;Weekly;None
+type OutlookSeasonality string
+
+const (
+	// OutlookSeasonalityAuto models a weekly cycle while the anime is airing
+	OutlookSeasonalityAuto   OutlookSeasonality = "Auto"
+	OutlookSeasonalityWeekly OutlookSeasonality = "Weekly"
+	OutlookSeasonalityNone   OutlookSeasonality = "None"
+)
+
+// OutlookSpec configures the short-term projection of the activity score
+type OutlookSpec struct {
+	// Horizon is how far ahead to project, up to 7 days (default 24h)
+	// +optional
+	Horizon *metav1.Duration `json:"horizon,omitempty"`
+
+	// Bucket is the length of each projected time bucket (default 1h for
+	// horizons up to 48h, 6h beyond)
+	// +optional
+	Bucket *metav1.Duration `json:"bucket,omitempty"`
+
+	// Seasonality selects whether a weekly cycle is modelled, e.g. for
+	// weekly episode releases. Auto models it while the anime is airing
+	// +kubebuilder:default=Auto
+	// +optional
+	Seasonality OutlookSeasonality `json:"seasonality,omitempty"`
+}
+
+// ActivityOutlook is the projected activity for the coming time buckets
+type ActivityOutlook struct {
+	// Method is the model used: Holt (linear trend) or HoltWinters (trend
+	// with a weekly cycle)
+	Method string `json:"method"`
+
+	// GeneratedAt is when the projection was made
+	GeneratedAt metav1.Time `json:"generatedAt"`
+
+	// Buckets are the projected time buckets, soonest first
+	Buckets []OutlookBucket `json:"buckets"`
+}
+
+// OutlookBucket is the projected activity for one time bucket
+type OutlookBucket struct {
+	// Start is when the bucket begins
+	Start metav1.Time `json:"start"`
+
+	// Score is the projected activity score
+	Score int `json:"score"`
+
+	// Lower and Upper bound the ~95% confidence band of the score
+	Lower int `json:"lower"`
+	Upper int `json:"upper"`
+
+	// Level is the activity level of the projected score
+	Level ActivityLevel `json:"level"`
+}
+
+// PendingLevelTransition is a level change waiting out spec.levelTransitions.minDwell
+type PendingLevelTransition struct {
+	// Level is the level the monitor will move to
+	Level ActivityLevel `json:"level"`
+
+	// Since is when the score first indicated the new level
+	Since metav1.Time `json:"since"`
+}
+
+// WeatherCondition is the weather an activity level is reported as
+// +kubebuilder:validation:Enum=Clear;Cloudy;Storm;Typhoon
+type WeatherCondition string
+
+const (
+	WeatherConditionClear   WeatherCondition = "Clear"
+	WeatherConditionCloudy  WeatherCondition = "Cloudy"
+	WeatherConditionStorm   WeatherCondition = "Storm"
+	WeatherConditionTyphoon WeatherCondition = "Typhoon"
+)
+
+// WeatherForecast is the monitor's current activity as weather, for
+// rendering weather widgets
+type WeatherForecast struct {
+	// Condition is the weather for the activity level: Clear (Low), Cloudy
+	// (Medium), Storm (High) or Typhoon (Critical)
+	Condition WeatherCondition `json:"condition"`
+
+	// Icon is the weather icon for the condition
+	Icon string `json:"icon"`
+
+	// TemperatureCelsius is derived from the MAL score, from -15 at 0 to 35 at 10
+	TemperatureCelsius int `json:"temperatureCelsius"`
+
+	// WindSpeedKmh is derived from member growth per hour since the previous check
+	WindSpeedKmh int `json:"windSpeedKmh"`
+
+	// PrecipitationChance is the chance, in percent, of reaching the next
+	// activity level if the score keeps its current trend
+	PrecipitationChance int `json:"precipitationChance"`
 }
 
+// ThresholdMode selects how activity level thresholds are chosen
+// +kubebuilder:validation:Enum=Manual;Auto
+type ThresholdMode string
+
+const (
+	ThresholdModeManual ThresholdMode = "Manual"
+	ThresholdModeAuto   ThresholdMode = "Auto"
+)
+
+// ThresholdSource records where the thresholds in effect came from
+// +kubebuilder:validation:Enum=Manual;Auto;Ladder
+type ThresholdSource string
+
+const (
+	ThresholdSourceManual ThresholdSource = "Manual"
+	ThresholdSourceAuto   ThresholdSource = "Auto"
+	ThresholdSourceLadder ThresholdSource = "Ladder"
+)
+
+// AutoThresholdsSpec configures automatic threshold calibration
+type AutoThresholdsSpec struct {
+	// Window is how far back recorded activity scores are considered
+	// +kubebuilder:default="168h"
+	// +optional
+	Window *metav1.Duration `json:"window,omitempty"`
+
+	// RecalculateInterval is how often the thresholds are recomputed
+	// +kubebuilder:default="1h"
+	// +optional
+	RecalculateInterval *metav1.Duration `json:"recalculateInterval,omitempty"`
+
+	// MediumPercentile is the percentile of recorded scores where Medium starts
+	// +kubebuilder:default=50
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=99
+	// +optional
+	MediumPercentile int `json:"mediumPercentile,omitempty"`
+
+	// HighPercentile is the percentile of recorded scores where High starts
+	// +kubebuilder:default=80
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=99
+	// +optional
+	HighPercentile int `json:"highPercentile,omitempty"`
+
+	// CriticalPercentile is the percentile of recorded scores where Critical starts
+	// +kubebuilder:default=95
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=99
+	// +optional
+	CriticalPercentile int `json:"criticalPercentile,omitempty"`
+
+	// MinSamples is the number of recorded scores required before the
+	// calibrated thresholds replace the manual ones
+	// +kubebuilder:default=12
+	// +kubebuilder:validation:Minimum=2
+	// +optional
+	MinSamples int `json:"minSamples,omitempty"`
+}
+
+// ActivityThresholds are the score cutoffs in effect for each activity level
+type ActivityThresholds struct {
+	// Medium is the lowest score reported as Medium
+	Medium int `json:"medium"`
+
+	// High is the lowest score reported as High
+	High int `json:"high"`
+
+	// Critical is the lowest score reported as Critical
+	Critical int `json:"critical"`
+
+	// Source is Auto when the thresholds were calibrated from history,
+	// Ladder when they are derived from spec.levelLadder, or Manual when
+	// they come from the spec (including while Auto mode is still
+	// collecting samples)
+	Source ThresholdSource `json:"source"`
+
+	// Samples is the number of recorded scores the calibration was based on
+	// +optional
+	Samples int `json:"samples,omitempty"`
+
+	// CalculatedAt is when the thresholds were last calibrated
+	// +optional
+	CalculatedAt *metav1.Time `json:"calculatedAt,omitempty"`
+}
+
+// ScoringWeights are the weights and bonuses of the Absolute activity score
+type ScoringWeights struct {
+	// ActiveUsers is the points per estimated active user
+	// +kubebuilder:default=10
+	// +kubebuilder:validation:Minimum=0
+	ActiveUsers int `json:"activeUsers"`
+
+	// Watching is the points per user currently watching
+	// +kubebuilder:default=5
+	// +kubebuilder:validation:Minimum=0
+	Watching int `json:"watching"`
+
+	// MembersPerPoint is how many members are worth one point
+	// +kubebuilder:default=1000
+	// +kubebuilder:validation:Minimum=1
+	MembersPerPoint int `json:"membersPerPoint"`
+
+	// FavoritesPerPoint is how many favorites are worth one point
+	// +kubebuilder:default=100
+	// +kubebuilder:validation:Minimum=1
+	FavoritesPerPoint int `json:"favoritesPerPoint"`
+
+	// HighScoreBonus is added when the MAL score exceeds HighScoreThreshold
+	// +kubebuilder:default=500
+	// +kubebuilder:validation:Minimum=0
+	HighScoreBonus int `json:"highScoreBonus"`
+
+	// HighScoreThreshold is the MAL score above which HighScoreBonus applies
+	// +kubebuilder:default=8
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=10
+	HighScoreThreshold float64 `json:"highScoreThreshold"`
+}
+
+// AnomalyDetectionSpec configures statistical anomaly detection
+type AnomalyDetectionSpec struct {
+	// ZScoreThreshold is how many standard deviations from the rolling mean
+	// a metric must move to be flagged as an anomaly
+	// +kubebuilder:default=3
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:ExclusiveMinimum=true
+	// +optional
+	ZScoreThreshold float64 `json:"zScoreThreshold,omitempty"`
+
+	// MinSamples is the number of history samples required before anomalies are detected
+	// +kubebuilder:default=6
+	// +kubebuilder:validation:Minimum=3
+	// +optional
+	MinSamples int `json:"minSamples,omitempty"`
+}
+
+// ScoringMode selects how the activity score is computed
+// +kubebuilder:validation:Enum=Absolute;Momentum;Expression
+type ScoringMode string
+
+const (
+	ScoringModeAbsolute   ScoringMode = "Absolute"
+	ScoringModeMomentum   ScoringMode = "Momentum"
+	ScoringModeExpression ScoringMode = "Expression"
+)
+
 // ActivityLevel represents the current activity state
 // +kubebuilder:validation:Enum=Low;Medium;High;Critical
 type ActivityLevel string
@@ -49,6 +402,40 @@ const (
 	ActivityLevelCritical ActivityLevel = "Critical"
 )
 
+// Rank orders the built-in levels from calmest (0) to stormiest (3). Unknown
+// levels rank -1.
+func (l ActivityLevel) Rank() int {
+	switch l {
+	case ActivityLevelLow:
+		return 0
+	case ActivityLevelMedium:
+		return 1
+	case ActivityLevelHigh:
+		return 2
+	case ActivityLevelCritical:
+		return 3
+	default:
+		return -1
+	}
+}
+
+// Icon returns the weather icon for the level, matching the mapping used by
+// scripts/sync-to-local.sh
+func (l ActivityLevel) Icon() string {
+	switch l {
+	case ActivityLevelCritical:
+		return "🌀"
+	case ActivityLevelHigh:
+		return "⛈️"
+	case ActivityLevelMedium:
+		return "⛅"
+	case ActivityLevelLow:
+		return "☀️"
+	default:
+		return "❓"
+	}
+}
+
 // AnimeActivityMetrics contains detailed activity metrics
 type AnimeActivityMetrics struct {
 	// ActiveUsers is the estimated number of active users
@@ -85,6 +472,48 @@ type AnimeActivityMetrics struct {
 	Favorites int `json:"favorites,omitempty"`
 }
 
+// ActivitySample is a single historical observation of a monitor
+type ActivitySample struct {
+	// Timestamp is when the sample was taken
+	Timestamp metav1.Time `json:"timestamp"`
+
+	// Members is the total member count
+	Members int `json:"members,omitempty"`
+
+	// Watching is the number of users currently watching
+	Watching int `json:"watching,omitempty"`
+
+	// Score is the MAL score
+	Score float64 `json:"score,omitempty"`
+
+	// Rank is the popularity rank
+	Rank int `json:"rank,omitempty"`
+
+	// ActivityScore is the computed activity score
+	ActivityScore int `json:"activityScore"`
+
+	// Level is the activity level determined from the activity score
+	Level ActivityLevel `json:"level"`
+}
+
+// MetricAnomaly describes a metric that deviated from its rolling baseline
+type MetricAnomaly struct {
+	// Metric is the name of the metric, e.g. watching or memberGrowth
+	Metric string `json:"metric"`
+
+	// Value is the metric's current value
+	Value float64 `json:"value"`
+
+	// Mean is the metric's rolling mean over history
+	Mean float64 `json:"mean"`
+
+	// StdDev is the metric's rolling standard deviation over history
+	StdDev float64 `json:"stdDev"`
+
+	// ZScore is how many standard deviations the value is from the mean
+	ZScore float64 `json:"zScore"`
+}
+
 // TrendingAnime represents a trending anime entry
 type TrendingAnime struct {
 	// ID is the MAL anime ID
@@ -106,6 +535,26 @@ type TrendingAnime struct {
 	ImageURL string `json:"imageUrl,omitempty"`
 }
 
+// BroadcastSchedule describes when new episodes of an airing anime are broadcast
+type BroadcastSchedule struct {
+	// Day is the weekly broadcast day (e.g., "Saturdays")
+	Day string `json:"day"`
+
+	// Time is the local broadcast time in HH:MM
+	Time string `json:"time"`
+
+	// Timezone is the IANA timezone of the broadcast time (e.g., "Asia/Tokyo")
+	Timezone string `json:"timezone"`
+
+	// Episodes is the total number of episodes, when MAL knows it
+	// +optional
+	Episodes int `json:"episodes,omitempty"`
+
+	// Premiered is the date the first episode aired, when MAL knows it
+	// +optional
+	Premiered *metav1.Time `json:"premiered,omitempty"`
+}
+
 // AnimeMonitorStatus defines the observed state of AnimeMonitor
 type AnimeMonitorStatus struct {
 	// Phase represents the current phase of the monitor
@@ -115,10 +564,23 @@ type AnimeMonitorStatus struct {
 	// ActivityLevel indicates the current activity level
 	ActivityLevel ActivityLevel `json:"activityLevel,omitempty"`
 
+	// CustomLevel is the monitor's level on spec.levelLadder, when set
+	// +optional
+	CustomLevel string `json:"customLevel,omitempty"`
+
+	// CustomLevelIcon is the icon of CustomLevel
+	// +optional
+	CustomLevelIcon string `json:"customLevelIcon,omitempty"`
+
 	// WeebcastStatus indicates the derived status for weebcast.com
 	// High MAL activity = High Weebcast engagement expected
 	WeebcastStatus string `json:"weebcastStatus,omitempty"`
 
+	// LocalizedStatus is WeebcastStatus in every locale with a message
+	// catalog, keyed by locale (e.g. en, ja, es, pt)
+	// +optional
+	LocalizedStatus map[string]string `json:"localizedStatus,omitempty"`
+
 	// Metrics contains detailed activity metrics
 	Metrics AnimeActivityMetrics `json:"metrics,omitempty"`
 
@@ -126,14 +588,54 @@ type AnimeMonitorStatus struct {
 	// +optional
 	TrendingAnime []TrendingAnime `json:"trendingAnime,omitempty"`
 
+	// TrendingAnimeUpdated is when TrendingAnime was last fetched. A failed
+	// fetch keeps the previous list, so an update older than LastChecked
+	// marks it stale.
+	// +optional
+	TrendingAnimeUpdated *metav1.Time `json:"trendingAnimeUpdated,omitempty"`
+
 	// SeasonalAnime lists anime from the current season
 	// +optional
 	SeasonalAnime []TrendingAnime `json:"seasonalAnime,omitempty"`
 
+	// SeasonalAnimeUpdated is when SeasonalAnime was last fetched, stale
+	// like TrendingAnimeUpdated when older than LastChecked
+	// +optional
+	SeasonalAnimeUpdated *metav1.Time `json:"seasonalAnimeUpdated,omitempty"`
+
+	// Broadcast is the weekly broadcast slot of the monitored anime while it is airing
+	// +optional
+	Broadcast *BroadcastSchedule `json:"broadcast,omitempty"`
+
 	// CurrentSeason indicates the current anime season (e.g., "Winter 2025")
 	// +optional
 	CurrentSeason string `json:"currentSeason,omitempty"`
 
+	// History holds the most recent samples, oldest first, bounded by spec.historySize
+	// +optional
+	History []ActivitySample `json:"history,omitempty"`
+
+	// Anomalies lists the metrics flagged by anomaly detection on the last check
+	// +optional
+	Anomalies []MetricAnomaly `json:"anomalies,omitempty"`
+
+	// EffectiveThresholds are the level thresholds used on the last check
+	// +optional
+	EffectiveThresholds *ActivityThresholds `json:"effectiveThresholds,omitempty"`
+
+	// PendingTransition is a level change that has not yet been committed
+	// because it has not persisted for spec.levelTransitions.minDwell
+	// +optional
+	PendingTransition *PendingLevelTransition `json:"pendingTransition,omitempty"`
+
+	// Forecast is the current activity as structured weather
+	// +optional
+	Forecast *WeatherForecast `json:"forecast,omitempty"`
+
+	// Outlook is the projected activity, when spec.outlook is set
+	// +optional
+	Outlook *ActivityOutlook `json:"outlook,omitempty"`
+
 	// LastChecked is the timestamp of the last activity check
 	LastChecked metav1.Time `json:"lastChecked,omitempty"`
 
@@ -150,6 +652,7 @@ type AnimeMonitorStatus struct {
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="Activity",type="string",JSONPath=".status.activityLevel",description="Current activity level"
+// +kubebuilder:printcolumn:name="Level",type="string",JSONPath=".status.customLevel",description="Level on the custom ladder",priority=1
 // +kubebuilder:printcolumn:name="Weebcast",type="string",JSONPath=".status.weebcastStatus",description="Weebcast status"
 // +kubebuilder:printcolumn:name="Score",type="number",JSONPath=".status.metrics.score",description="MAL Score"
 // +kubebuilder:printcolumn:name="Members",type="integer",JSONPath=".status.metrics.members",description="Member count"
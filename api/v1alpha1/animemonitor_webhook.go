@@ -0,0 +1,101 @@
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/weebcast/weebcast-operator/pkg/scoring"
+)
+
+// SetupWebhookWithManager registers the AnimeMonitor validating webhook
+func (r *AnimeMonitor) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		WithValidator(&animeMonitorValidator{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-weebcast-com-v1alpha1-animemonitor,mutating=false,failurePolicy=fail,sideEffects=None,groups=weebcast.com,resources=animemonitors,verbs=create;update,versions=v1alpha1,name=vanimemonitor.weebcast.com,admissionReviewVersions=v1
+
+// animeMonitorValidator rejects AnimeMonitors whose spec cannot be
+// evaluated, such as a scoring expression that does not compile or an
+// unordered level ladder
+type animeMonitorValidator struct{}
+
+var _ admission.CustomValidator = &animeMonitorValidator{}
+
+// ValidateCreate validates a new AnimeMonitor
+func (v *animeMonitorValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, v.validate(obj)
+}
+
+// ValidateUpdate validates an updated AnimeMonitor
+func (v *animeMonitorValidator) ValidateUpdate(_ context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+	return nil, v.validate(newObj)
+}
+
+// ValidateDelete allows every deletion
+func (v *animeMonitorValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validate checks the parts of the spec the CRD schema cannot express
+func (v *animeMonitorValidator) validate(obj runtime.Object) error {
+	monitor, ok := obj.(*AnimeMonitor)
+	if !ok {
+		return fmt.Errorf("expected an AnimeMonitor but got %T", obj)
+	}
+
+	var errs field.ErrorList
+	if monitor.Spec.ScoringMode == ScoringModeExpression {
+		path := field.NewPath("spec", "scoringExpression")
+		if err := scoring.Validate(monitor.Spec.ScoringExpression); err != nil {
+			errs = append(errs, field.Invalid(path, monitor.Spec.ScoringExpression, err.Error()))
+		}
+	}
+
+	if err := ValidateLevelLadder(monitor.Spec.LevelLadder); err != nil {
+		errs = append(errs, field.Invalid(field.NewPath("spec", "levelLadder"), len(monitor.Spec.LevelLadder), err.Error()))
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(GroupVersion.WithKind("AnimeMonitor").GroupKind(), monitor.Name, errs)
+}
+
+// ValidateLevelLadder checks that ladder steps have unique names, strictly
+// ascending thresholds and built-in levels that never go down the ladder
+func ValidateLevelLadder(steps []LevelStep) error {
+	names := make(map[string]bool, len(steps))
+	for i, step := range steps {
+		if names[step.Name] {
+			return fmt.Errorf("level %q is defined more than once", step.Name)
+		}
+		names[step.Name] = true
+
+		if step.MapsTo.Rank() < 0 {
+			return fmt.Errorf("level %q maps to unknown level %q", step.Name, step.MapsTo)
+		}
+		if i == 0 {
+			continue
+		}
+
+		previous := steps[i-1]
+		if step.Threshold <= previous.Threshold {
+			return fmt.Errorf("level %q threshold %d must be above %q threshold %d",
+				step.Name, step.Threshold, previous.Name, previous.Threshold)
+		}
+		if step.MapsTo.Rank() < previous.MapsTo.Rank() {
+			return fmt.Errorf("level %q maps to %s, below %s of the preceding level %q",
+				step.Name, step.MapsTo, previous.MapsTo, previous.Name)
+		}
+	}
+	return nil
+}
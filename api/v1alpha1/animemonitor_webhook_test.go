@@ -0,0 +1,78 @@
+package v1alpha1
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateLevelLadder(t *testing.T) {
+	tests := []struct {
+		name    string
+		steps   []LevelStep
+		wantErr string
+	}{
+		{name: "empty"},
+		{
+			name: "valid",
+			steps: []LevelStep{
+				{Name: "Calm", Threshold: 0, MapsTo: ActivityLevelLow},
+				{Name: "Breezy", Threshold: 300, MapsTo: ActivityLevelLow},
+				{Name: "Gale", Threshold: 600, MapsTo: ActivityLevelMedium},
+				{Name: "Apocalypse", Threshold: 5000, MapsTo: ActivityLevelCritical},
+			},
+		},
+		{
+			name: "duplicate name",
+			steps: []LevelStep{
+				{Name: "Calm", Threshold: 0, MapsTo: ActivityLevelLow},
+				{Name: "Calm", Threshold: 300, MapsTo: ActivityLevelMedium},
+			},
+			wantErr: `level "Calm" is defined more than once`,
+		},
+		{
+			name:    "unknown level",
+			steps:   []LevelStep{{Name: "Calm", Threshold: 0, MapsTo: "Severe"}},
+			wantErr: `maps to unknown level "Severe"`,
+		},
+		{
+			name: "equal thresholds",
+			steps: []LevelStep{
+				{Name: "Calm", Threshold: 300, MapsTo: ActivityLevelLow},
+				{Name: "Gale", Threshold: 300, MapsTo: ActivityLevelMedium},
+			},
+			wantErr: `level "Gale" threshold 300 must be above "Calm" threshold 300`,
+		},
+		{
+			name: "descending thresholds",
+			steps: []LevelStep{
+				{Name: "Calm", Threshold: 300, MapsTo: ActivityLevelLow},
+				{Name: "Gale", Threshold: 200, MapsTo: ActivityLevelMedium},
+			},
+			wantErr: "must be above",
+		},
+		{
+			name: "level steps down",
+			steps: []LevelStep{
+				{Name: "Calm", Threshold: 0, MapsTo: ActivityLevelLow},
+				{Name: "Storm", Threshold: 600, MapsTo: ActivityLevelHigh},
+				{Name: "Lull", Threshold: 900, MapsTo: ActivityLevelMedium},
+			},
+			wantErr: `level "Lull" maps to Medium, below High of the preceding level "Storm"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateLevelLadder(tt.steps)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ValidateLevelLadder() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidateLevelLadder() error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -10,6 +10,64 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActivityOutlook) DeepCopyInto(out *ActivityOutlook) {
+	*out = *in
+	in.GeneratedAt.DeepCopyInto(&out.GeneratedAt)
+	if in.Buckets != nil {
+		in, out := &in.Buckets, &out.Buckets
+		*out = make([]OutlookBucket, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActivityOutlook.
+func (in *ActivityOutlook) DeepCopy() *ActivityOutlook {
+	if in == nil {
+		return nil
+	}
+	out := new(ActivityOutlook)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActivitySample) DeepCopyInto(out *ActivitySample) {
+	*out = *in
+	in.Timestamp.DeepCopyInto(&out.Timestamp)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActivitySample.
+func (in *ActivitySample) DeepCopy() *ActivitySample {
+	if in == nil {
+		return nil
+	}
+	out := new(ActivitySample)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActivityThresholds) DeepCopyInto(out *ActivityThresholds) {
+	*out = *in
+	if in.CalculatedAt != nil {
+		in, out := &in.CalculatedAt, &out.CalculatedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActivityThresholds.
+func (in *ActivityThresholds) DeepCopy() *ActivityThresholds {
+	if in == nil {
+		return nil
+	}
+	out := new(ActivityThresholds)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AnimeActivityMetrics) DeepCopyInto(out *AnimeActivityMetrics) {
 	*out = *in
@@ -30,7 +88,7 @@ func (in *AnimeMonitor) DeepCopyInto(out *AnimeMonitor) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -87,6 +145,41 @@ func (in *AnimeMonitorList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AnimeMonitorSpec) DeepCopyInto(out *AnimeMonitorSpec) {
 	*out = *in
+	if in.HistorySize != nil {
+		in, out := &in.HistorySize, &out.HistorySize
+		*out = new(int)
+		**out = **in
+	}
+	if in.ScoringWeights != nil {
+		in, out := &in.ScoringWeights, &out.ScoringWeights
+		*out = new(ScoringWeights)
+		**out = **in
+	}
+	if in.AnomalyDetection != nil {
+		in, out := &in.AnomalyDetection, &out.AnomalyDetection
+		*out = new(AnomalyDetectionSpec)
+		**out = **in
+	}
+	if in.AutoThresholds != nil {
+		in, out := &in.AutoThresholds, &out.AutoThresholds
+		*out = new(AutoThresholdsSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LevelLadder != nil {
+		in, out := &in.LevelLadder, &out.LevelLadder
+		*out = make([]LevelStep, len(*in))
+		copy(*out, *in)
+	}
+	if in.Outlook != nil {
+		in, out := &in.Outlook, &out.Outlook
+		*out = new(OutlookSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LevelTransitions != nil {
+		in, out := &in.LevelTransitions, &out.LevelTransitions
+		*out = new(LevelTransitionSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AnimeMonitorSpec.
@@ -102,17 +195,69 @@ func (in *AnimeMonitorSpec) DeepCopy() *AnimeMonitorSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AnimeMonitorStatus) DeepCopyInto(out *AnimeMonitorStatus) {
 	*out = *in
+	if in.LocalizedStatus != nil {
+		in, out := &in.LocalizedStatus, &out.LocalizedStatus
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	in.Metrics.DeepCopyInto(&out.Metrics)
 	if in.TrendingAnime != nil {
 		in, out := &in.TrendingAnime, &out.TrendingAnime
 		*out = make([]TrendingAnime, len(*in))
 		copy(*out, *in)
 	}
+	if in.TrendingAnimeUpdated != nil {
+		in, out := &in.TrendingAnimeUpdated, &out.TrendingAnimeUpdated
+		*out = (*in).DeepCopy()
+	}
 	if in.SeasonalAnime != nil {
 		in, out := &in.SeasonalAnime, &out.SeasonalAnime
 		*out = make([]TrendingAnime, len(*in))
 		copy(*out, *in)
 	}
+	if in.SeasonalAnimeUpdated != nil {
+		in, out := &in.SeasonalAnimeUpdated, &out.SeasonalAnimeUpdated
+		*out = (*in).DeepCopy()
+	}
+	if in.Broadcast != nil {
+		in, out := &in.Broadcast, &out.Broadcast
+		*out = new(BroadcastSchedule)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = make([]ActivitySample, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Anomalies != nil {
+		in, out := &in.Anomalies, &out.Anomalies
+		*out = make([]MetricAnomaly, len(*in))
+		copy(*out, *in)
+	}
+	if in.EffectiveThresholds != nil {
+		in, out := &in.EffectiveThresholds, &out.EffectiveThresholds
+		*out = new(ActivityThresholds)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PendingTransition != nil {
+		in, out := &in.PendingTransition, &out.PendingTransition
+		*out = new(PendingLevelTransition)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Forecast != nil {
+		in, out := &in.Forecast, &out.Forecast
+		*out = new(WeatherForecast)
+		**out = **in
+	}
+	if in.Outlook != nil {
+		in, out := &in.Outlook, &out.Outlook
+		*out = new(ActivityOutlook)
+		(*in).DeepCopyInto(*out)
+	}
 	in.LastChecked.DeepCopyInto(&out.LastChecked)
 	in.LastActivityChange.DeepCopyInto(&out.LastActivityChange)
 	if in.Conditions != nil {
@@ -134,6 +279,187 @@ func (in *AnimeMonitorStatus) DeepCopy() *AnimeMonitorStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AnomalyDetectionSpec) DeepCopyInto(out *AnomalyDetectionSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AnomalyDetectionSpec.
+func (in *AnomalyDetectionSpec) DeepCopy() *AnomalyDetectionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AnomalyDetectionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoThresholdsSpec) DeepCopyInto(out *AutoThresholdsSpec) {
+	*out = *in
+	if in.Window != nil {
+		in, out := &in.Window, &out.Window
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.RecalculateInterval != nil {
+		in, out := &in.RecalculateInterval, &out.RecalculateInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoThresholdsSpec.
+func (in *AutoThresholdsSpec) DeepCopy() *AutoThresholdsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoThresholdsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BroadcastSchedule) DeepCopyInto(out *BroadcastSchedule) {
+	*out = *in
+	if in.Premiered != nil {
+		in, out := &in.Premiered, &out.Premiered
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BroadcastSchedule.
+func (in *BroadcastSchedule) DeepCopy() *BroadcastSchedule {
+	if in == nil {
+		return nil
+	}
+	out := new(BroadcastSchedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LevelStep) DeepCopyInto(out *LevelStep) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LevelStep.
+func (in *LevelStep) DeepCopy() *LevelStep {
+	if in == nil {
+		return nil
+	}
+	out := new(LevelStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LevelTransitionSpec) DeepCopyInto(out *LevelTransitionSpec) {
+	*out = *in
+	if in.MinDwell != nil {
+		in, out := &in.MinDwell, &out.MinDwell
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LevelTransitionSpec.
+func (in *LevelTransitionSpec) DeepCopy() *LevelTransitionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LevelTransitionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetricAnomaly) DeepCopyInto(out *MetricAnomaly) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricAnomaly.
+func (in *MetricAnomaly) DeepCopy() *MetricAnomaly {
+	if in == nil {
+		return nil
+	}
+	out := new(MetricAnomaly)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OutlookBucket) DeepCopyInto(out *OutlookBucket) {
+	*out = *in
+	in.Start.DeepCopyInto(&out.Start)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OutlookBucket.
+func (in *OutlookBucket) DeepCopy() *OutlookBucket {
+	if in == nil {
+		return nil
+	}
+	out := new(OutlookBucket)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OutlookSpec) DeepCopyInto(out *OutlookSpec) {
+	*out = *in
+	if in.Horizon != nil {
+		in, out := &in.Horizon, &out.Horizon
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Bucket != nil {
+		in, out := &in.Bucket, &out.Bucket
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OutlookSpec.
+func (in *OutlookSpec) DeepCopy() *OutlookSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OutlookSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PendingLevelTransition) DeepCopyInto(out *PendingLevelTransition) {
+	*out = *in
+	in.Since.DeepCopyInto(&out.Since)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PendingLevelTransition.
+func (in *PendingLevelTransition) DeepCopy() *PendingLevelTransition {
+	if in == nil {
+		return nil
+	}
+	out := new(PendingLevelTransition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScoringWeights) DeepCopyInto(out *ScoringWeights) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScoringWeights.
+func (in *ScoringWeights) DeepCopy() *ScoringWeights {
+	if in == nil {
+		return nil
+	}
+	out := new(ScoringWeights)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TrendingAnime) DeepCopyInto(out *TrendingAnime) {
 	*out = *in
@@ -148,3 +474,18 @@ func (in *TrendingAnime) DeepCopy() *TrendingAnime {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WeatherForecast) DeepCopyInto(out *WeatherForecast) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WeatherForecast.
+func (in *WeatherForecast) DeepCopy() *WeatherForecast {
+	if in == nil {
+		return nil
+	}
+	out := new(WeatherForecast)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -1,11 +1,24 @@
 package main
 
 import (
+	"bufio"
+	"context"
+	"encoding/json"
 	"flag"
+	"fmt"
+	"io"
 	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	// Embed the timezone database for broadcast times in the distroless image
+	_ "time/tzdata"
 
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
@@ -13,8 +26,14 @@ import (
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 
 	weebcastv1alpha1 "github.com/weebcast/weebcast-operator/api/v1alpha1"
+	"github.com/weebcast/weebcast-operator/config/crd"
 	"github.com/weebcast/weebcast-operator/internal/controller"
+	"github.com/weebcast/weebcast-operator/internal/server"
+	"github.com/weebcast/weebcast-operator/pkg/badge"
+	"github.com/weebcast/weebcast-operator/pkg/feed"
+	"github.com/weebcast/weebcast-operator/pkg/history"
 	"github.com/weebcast/weebcast-operator/pkg/mal"
+	"github.com/weebcast/weebcast-operator/pkg/webhook"
 )
 
 var (
@@ -28,15 +47,82 @@ func init() {
 }
 
 func main() {
+	// Subcommands run one-off tools instead of the manager
+	if len(os.Args) > 1 && os.Args[1] == "export" {
+		if err := runExport(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, "export:", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "backtest" {
+		if err := runBacktest(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, "backtest:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
+	var enableWebhooks bool
+	var apiAddr string
+	var feedBaseURL string
+	var feedPublish bool
+	var badgePublish bool
+	var publishers string
+	var pubOpts publisherOptions
+	var historyPath string
+	var messageTemplates string
+	historyPolicy := history.DefaultPolicy
 
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.BoolVar(&enableWebhooks, "enable-webhooks", false,
+		"Serve the AnimeMonitor validating webhook on :9443. Requires serving certificates, see config/webhook.")
+	flag.StringVar(&apiAddr, "api-bind-address", "0",
+		"The address the read-only weebcast API binds to, e.g. :8787. Set to 0 to disable.")
+	flag.StringVar(&feedBaseURL, "feed-base-url", "https://weebcast.com",
+		"Public base URL used for Atom feed IDs and links.")
+	flag.BoolVar(&feedPublish, "feed-publish", false,
+		"Write the Atom and iCalendar feeds to the s3 and static publishers.")
+	flag.BoolVar(&badgePublish, "badge-publish", false,
+		"Write SVG status badges to the s3 and static publishers.")
+	flag.StringVar(&historyPath, "history-db", "",
+		"Path of the embedded time-series store (e.g. on a mounted PVC) that keeps every sample. Empty disables it.")
+	flag.DurationVar(&historyPolicy.Retention, "history-retention", history.DefaultPolicy.Retention,
+		"How long samples are kept in the time-series store.")
+	flag.DurationVar(&historyPolicy.DownsampleAfter, "history-downsample-after", history.DefaultPolicy.DownsampleAfter,
+		"Age after which stored samples are downsampled to hourly averages.")
+	flag.StringVar(&messageTemplates, "message-templates", "",
+		"ConfigMap (namespace/name) with WeebcastStatus message templates for monitors that do not set spec.messageTemplatesConfigMap.")
+	flag.StringVar(&publishers, "publishers", "cloudflare-kv",
+		"Comma-separated publisher backends to push activity to (cloudflare-kv, s3, redis, static).")
+	flag.StringVar(&pubOpts.cloudflareAPIURL, "cloudflare-api-url", webhook.DefaultCloudflareAPIURL,
+		"Base URL of the Cloudflare API. Override to point at a local stand-in server.")
+	flag.StringVar(&pubOpts.purgeURLs, "cloudflare-purge-urls", "",
+		"Comma-separated URLs to purge from the Cloudflare cache after publishing. "+
+			"{animeId} is replaced with the monitored anime's MAL ID.")
+	flag.DurationVar(&pubOpts.purgeInterval, "cloudflare-purge-interval", 10*time.Second,
+		"How often queued cache purges are sent to Cloudflare.")
+	flag.StringVar(&pubOpts.s3.Endpoint, "s3-endpoint", "", "S3-compatible endpoint URL, e.g. http://minio:9000.")
+	flag.StringVar(&pubOpts.s3.Region, "s3-region", "us-east-1", "S3 region used for request signing.")
+	flag.StringVar(&pubOpts.s3.Bucket, "s3-bucket", "", "S3 bucket to publish activity documents to.")
+	flag.StringVar(&pubOpts.s3.Prefix, "s3-prefix", "", "Prefix prepended to every S3 object key, e.g. api/.")
+	flag.BoolVar(&pubOpts.s3.PathStyle, "s3-path-style", true, "Use path-style bucket addressing (required for MinIO).")
+	flag.StringVar(&pubOpts.s3.ContentType, "s3-content-type", "application/json", "Content-Type set on published objects.")
+	flag.StringVar(&pubOpts.s3.CacheControl, "s3-cache-control", "", "Cache-Control set on published objects.")
+	flag.StringVar(&pubOpts.staticDir, "static-dir", "",
+		"Directory (e.g. a mounted PVC) the static publisher renders the JSON API into.")
+	flag.StringVar(&pubOpts.redis.Addr, "redis-addr", "localhost:6379", "Redis server address (host:port).")
+	flag.IntVar(&pubOpts.redis.DB, "redis-db", 0, "Redis logical database.")
+	flag.StringVar(&pubOpts.redis.KeyPrefix, "redis-key-prefix", "weebcast:", "Prefix prepended to every Redis key.")
+	flag.StringVar(&pubOpts.redis.Channel, "redis-channel", "weebcast:activity",
+		"Redis channel that receives a message whenever an activity level changes.")
 
 	opts := zap.Options{
 		Development: true,
@@ -63,16 +149,118 @@ func main() {
 	// Create MAL client
 	malClient := mal.NewClient()
 
-	// Setup AnimeMonitor controller
-	if err = (&controller.AnimeMonitorReconciler{
+	reconciler := &controller.AnimeMonitorReconciler{
 		Client:    mgr.GetClient(),
 		Scheme:    mgr.GetScheme(),
 		MALClient: malClient,
-	}).SetupWithManager(mgr); err != nil {
+		Recorder:  mgr.GetEventRecorderFor("weebcast-operator"),
+		APIReader: mgr.GetAPIReader(),
+	}
+
+	// Keep every sample in the embedded time-series store
+	var historyStore *history.Store
+	if historyPath != "" {
+		historyStore, err = history.Open(historyPath, historyPolicy)
+		if err != nil {
+			setupLog.Error(err, "unable to open history store")
+			os.Exit(1)
+		}
+		if err := mgr.Add(historyStore); err != nil {
+			setupLog.Error(err, "unable to set up history store")
+			os.Exit(1)
+		}
+		reconciler.HistoryStore = historyStore
+	}
+
+	// Default message templates for every monitor
+	if messageTemplates != "" {
+		namespace, name, ok := strings.Cut(messageTemplates, "/")
+		if !ok || namespace == "" || name == "" {
+			setupLog.Error(fmt.Errorf("expected namespace/name, got %q", messageTemplates), "invalid --message-templates")
+			os.Exit(1)
+		}
+		reconciler.MessageTemplates = types.NamespacedName{Namespace: namespace, Name: name}
+	}
+
+	// Set up the publisher backends
+	reconciler.Publishers, err = newPublishers(mgr, splitList(publishers), pubOpts)
+	if err != nil {
+		setupLog.Error(err, "unable to set up publishers")
+		os.Exit(1)
+	}
+
+	// Feeds and badges can only be written next to the payloads by publishers
+	// that host static documents
+	documents := documentPublishers(reconciler.Publishers)
+	if (feedPublish || badgePublish) && len(documents) == 0 {
+		setupLog.Error(fmt.Errorf("no document publisher configured"),
+			"--feed-publish and --badge-publish require the s3 or static publisher", "publishers", publishers)
+		os.Exit(1)
+	}
+
+	// Record level transitions as Atom feeds and upcoming episodes as an
+	// iCalendar feed, served by the API and/or written next to the published payloads
+	apiEnabled := apiAddr != "0" && apiAddr != ""
+	var atomFeed *feed.AtomFeed
+	var calendar *feed.EpisodeCalendar
+	if apiEnabled || feedPublish {
+		atomFeed = feed.NewAtomFeed(feedBaseURL)
+		calendar = feed.NewEpisodeCalendar()
+		if feedPublish {
+			atomFeed.WithDocumentPublishers(documents...)
+			calendar.WithDocumentPublishers(documents...)
+		}
+		reconciler.Publishers = append(reconciler.Publishers, atomFeed, calendar)
+	}
+
+	// Render status badges, served by the API and/or written next to the
+	// published payloads
+	var badges *badge.Badges
+	if apiEnabled || badgePublish {
+		badges = badge.NewBadges()
+		if badgePublish {
+			badges.WithDocumentPublishers(documents...)
+		}
+		reconciler.Publishers = append(reconciler.Publishers, badges)
+	}
+
+	// Serve the worker-compatible API straight from the informer cache, with
+	// an event stream fed by the reconciler's publish step
+	if apiEnabled {
+		events := server.NewEventStream()
+		reconciler.Publishers = append(reconciler.Publishers, events)
+
+		// Events, feeds, badges and history are only fed on the leader
+		apiServer := server.New(mgr.GetClient(), apiAddr).
+			WithElected(mgr.Elected()).
+			HandleLeader("/api/events", events).
+			HandleLeader("/api/feed", atomFeed).
+			HandleLeader("/api/feed/", atomFeed).
+			HandleLeader("/api/calendar.ics", calendar).
+			HandleLeader("/api/badge/", badges)
+		if historyStore != nil {
+			apiServer.HandleLeader("/api/history/export", historyStore)
+		}
+		if err := mgr.Add(apiServer); err != nil {
+			setupLog.Error(err, "unable to set up API server")
+			os.Exit(1)
+		}
+	}
+
+	// Setup AnimeMonitor controller
+	if err = reconciler.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "AnimeMonitor")
 		os.Exit(1)
 	}
 
+	// Validate scoring expressions at admission
+	if enableWebhooks {
+		if err = (&weebcastv1alpha1.AnimeMonitor{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "AnimeMonitor")
+			os.Exit(1)
+		}
+	}
+
 	// Add health checks
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
@@ -92,3 +280,375 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// runExport implements the export subcommand, which writes stored history
+// as CSV or JSON Lines. The operator locks the store while running, so
+// export a copy of the file or use /api/history/export instead.
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	path := fs.String("history-db", "", "Path of the history store to export.")
+	monitor := fs.String("monitor", "", "Monitor to export as namespace/name. Empty exports all monitors.")
+	from := fs.String("from", "", "Start of the time range (RFC 3339 or YYYY-MM-DD).")
+	to := fs.String("to", "", "End of the time range, exclusive (RFC 3339 or YYYY-MM-DD).")
+	format := fs.String("format", history.FormatCSV, "Output format (csv or jsonl).")
+	output := fs.String("output", "", "File to write to. Defaults to stdout.")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *path == "" {
+		return fmt.Errorf("--history-db is required")
+	}
+
+	query := history.ExportQuery{Monitor: *monitor, Format: *format}
+	var err error
+	if query.From, err = history.ParseTime(*from); err != nil {
+		return err
+	}
+	if query.To, err = history.ParseTime(*to); err != nil {
+		return err
+	}
+
+	store, err := history.OpenReadOnly(*path)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	w := io.Writer(os.Stdout)
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return fmt.Errorf("creating %s: %w", *output, err)
+		}
+		defer file.Close()
+		w = file
+	}
+
+	buffered := bufio.NewWriter(w)
+	if err := store.Export(buffered, query); err != nil {
+		return err
+	}
+	return buffered.Flush()
+}
+
+// runBacktest replays recorded history through a monitor manifest's
+// settings and reports the transitions, notifications and time in each level
+// they would have produced, next to what was actually recorded
+func runBacktest(args []string) error {
+	fs := flag.NewFlagSet("backtest", flag.ExitOnError)
+	manifest := fs.String("monitor-file", "", "AnimeMonitor manifest with the candidate settings.")
+	name := fs.String("name", "", "AnimeMonitor to use when the manifest holds several.")
+	historyFile := fs.String("history", "", "History export (csv or jsonl) to replay.")
+	historyDB := fs.String("history-db", "", "History store to replay instead of an export file.")
+	source := fs.String("source-monitor", "", "Monitor (namespace/name) whose history to replay. Defaults to the manifest's monitor.")
+	format := fs.String("format", "", "Format of --history (csv or jsonl). Defaults to the file extension.")
+	from := fs.String("from", "", "Start of the replayed time range (RFC 3339 or YYYY-MM-DD).")
+	to := fs.String("to", "", "End of the replayed time range, exclusive (RFC 3339 or YYYY-MM-DD).")
+	output := fs.String("output", "text", "Report format (text or json).")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *manifest == "" {
+		return fmt.Errorf("--monitor-file is required")
+	}
+	if (*historyFile == "") == (*historyDB == "") {
+		return fmt.Errorf("exactly one of --history or --history-db is required")
+	}
+
+	monitor, err := readMonitorManifest(*manifest, *name)
+	if err != nil {
+		return err
+	}
+	key := *source
+	if key == "" {
+		key = monitor.Namespace + "/" + monitor.Name
+	}
+
+	fromTime, err := history.ParseTime(*from)
+	if err != nil {
+		return err
+	}
+	toTime, err := history.ParseTime(*to)
+	if err != nil {
+		return err
+	}
+
+	var samples []history.Sample
+	collect := func(monitor string, sample history.Sample) error {
+		if monitor == key {
+			samples = append(samples, sample)
+		}
+		return nil
+	}
+
+	if *historyDB != "" {
+		store, err := history.OpenReadOnly(*historyDB)
+		if err != nil {
+			return err
+		}
+		defer store.Close()
+		err = store.Range(key, fromTime, toTime, func(sample history.Sample) error {
+			return collect(key, sample)
+		})
+		if err != nil {
+			return err
+		}
+	} else {
+		file, err := os.Open(*historyFile)
+		if err != nil {
+			return fmt.Errorf("opening %s: %w", *historyFile, err)
+		}
+		defer file.Close()
+
+		if *format == "" {
+			*format = history.FormatCSV
+			if strings.HasSuffix(*historyFile, ".jsonl") {
+				*format = history.FormatJSONL
+			}
+		}
+		err = history.ReadExport(bufio.NewReader(file), *format, func(monitor string, sample history.Sample) error {
+			if (!fromTime.IsZero() && sample.Timestamp.Before(fromTime)) ||
+				(!toTime.IsZero() && !sample.Timestamp.Before(toTime)) {
+				return nil
+			}
+			return collect(monitor, sample)
+		})
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", *historyFile, err)
+		}
+	}
+
+	if len(samples) == 0 {
+		return fmt.Errorf("no samples recorded for %s", key)
+	}
+
+	report, err := controller.Backtest(context.Background(), monitor, samples)
+	if err != nil {
+		return err
+	}
+
+	switch *output {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	case "text":
+		return writeBacktestReport(os.Stdout, report)
+	default:
+		return fmt.Errorf("unsupported output %q", *output)
+	}
+}
+
+// readMonitorManifest reads an AnimeMonitor from a YAML or JSON manifest,
+// picking the one with the given name when the manifest holds several. The
+// CRD's schema defaults are applied, so the monitor matches what the API
+// server would store.
+func readMonitorManifest(path, name string) (*weebcastv1alpha1.AnimeMonitor, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening %s: %w", path, err)
+	}
+	defer file.Close()
+
+	var monitors []*weebcastv1alpha1.AnimeMonitor
+	decoder := utilyaml.NewYAMLOrJSONDecoder(file, 4096)
+	for {
+		obj := map[string]interface{}{}
+		if err := decoder.Decode(&obj); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("decoding %s: %w", path, err)
+		}
+		if kind, _ := obj["kind"].(string); kind != "AnimeMonitor" {
+			continue
+		}
+		if err := crd.ApplyDefaults(obj); err != nil {
+			return nil, err
+		}
+
+		monitor := &weebcastv1alpha1.AnimeMonitor{}
+		data, err := json.Marshal(obj)
+		if err == nil {
+			err = json.Unmarshal(data, monitor)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("decoding %s: %w", path, err)
+		}
+		if name != "" && monitor.Name != name {
+			continue
+		}
+		if monitor.Namespace == "" {
+			monitor.Namespace = "default"
+		}
+		monitors = append(monitors, monitor)
+	}
+
+	switch len(monitors) {
+	case 0:
+		return nil, fmt.Errorf("no matching AnimeMonitor in %s", path)
+	case 1:
+		return monitors[0], nil
+	default:
+		return nil, fmt.Errorf("%s holds %d AnimeMonitors, pick one with --name", path, len(monitors))
+	}
+}
+
+// writeBacktestReport prints a backtest report as a table
+func writeBacktestReport(w io.Writer, report *controller.BacktestReport) error {
+	fmt.Fprintf(w, "Monitor:  %s\n", report.Monitor)
+	fmt.Fprintf(w, "Samples:  %d (%s to %s)\n\n", report.Samples,
+		report.From.UTC().Format(time.RFC3339), report.To.UTC().Format(time.RFC3339))
+
+	total := report.To.Sub(report.From)
+	share := func(d time.Duration) string {
+		if total <= 0 {
+			return "-"
+		}
+		return fmt.Sprintf("%s (%.0f%%)", d.Round(time.Minute), float64(d)/float64(total)*100)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "\tCANDIDATE\tRECORDED")
+	fmt.Fprintf(tw, "Transitions\t%d\t%d\n", report.Candidate.Transitions, report.Recorded.Transitions)
+	fmt.Fprintf(tw, "Notifications\t%d\t%d\n", report.Candidate.Notifications, report.Recorded.Notifications)
+	for _, level := range []weebcastv1alpha1.ActivityLevel{
+		weebcastv1alpha1.ActivityLevelLow,
+		weebcastv1alpha1.ActivityLevelMedium,
+		weebcastv1alpha1.ActivityLevelHigh,
+		weebcastv1alpha1.ActivityLevelCritical,
+	} {
+		fmt.Fprintf(tw, "Time in %s\t%s\t%s\n", level,
+			share(report.Candidate.TimeInLevel[level]), share(report.Recorded.TimeInLevel[level]))
+	}
+	fmt.Fprintf(tw, "Final level\t%s\t%s\n", report.Candidate.FinalLevel, report.Recorded.FinalLevel)
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if len(report.Transitions) == 0 {
+		return nil
+	}
+	fmt.Fprintln(w, "\nCandidate transitions:")
+	tw = tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, t := range report.Transitions {
+		fmt.Fprintf(tw, "  %s\t%s -> %s\tscore %d\n", t.Time.UTC().Format(time.RFC3339), t.From, t.To, t.Score)
+	}
+	return tw.Flush()
+}
+
+// publisherOptions holds the flags for all publisher backends
+type publisherOptions struct {
+	cloudflareAPIURL string
+	purgeURLs        string
+	purgeInterval    time.Duration
+	s3               webhook.S3Config
+	redis            webhook.RedisConfig
+	staticDir        string
+}
+
+// newPublishers creates the named publisher backends. Credentials are read
+// from the environment, which the deployment populates from Secrets.
+func newPublishers(mgr ctrl.Manager, names []string, opts publisherOptions) ([]webhook.Publisher, error) {
+	var publishers []webhook.Publisher
+
+	for _, name := range names {
+		switch name {
+		case "cloudflare-kv":
+			accountID := os.Getenv("CLOUDFLARE_ACCOUNT_ID")
+			namespaceID := os.Getenv("CLOUDFLARE_KV_NAMESPACE_ID")
+			apiToken := os.Getenv("CLOUDFLARE_API_TOKEN")
+			if accountID == "" || namespaceID == "" || apiToken == "" {
+				setupLog.Info("Cloudflare credentials not set, skipping Cloudflare KV publisher")
+				continue
+			}
+
+			var publisher webhook.Publisher = webhook.NewCloudflareKVClient(accountID, namespaceID, apiToken).
+				WithBaseURL(opts.cloudflareAPIURL)
+
+			// Purge cached API responses after each successful publish
+			if urls := splitList(opts.purgeURLs); len(urls) > 0 {
+				zoneID := os.Getenv("CLOUDFLARE_ZONE_ID")
+				if zoneID == "" {
+					return nil, fmt.Errorf("CLOUDFLARE_ZONE_ID is required for cache purging")
+				}
+				purger := webhook.NewCloudflareCachePurger(zoneID, apiToken, urls).
+					WithBaseURL(opts.cloudflareAPIURL).
+					WithInterval(opts.purgeInterval)
+				if err := mgr.Add(purger); err != nil {
+					return nil, fmt.Errorf("adding cache purger: %w", err)
+				}
+				publisher = purger.Wrap(publisher)
+				setupLog.Info("purging Cloudflare cache after publishing", "urls", urls)
+			}
+
+			publishers = append(publishers, publisher)
+			setupLog.Info("publishing activity to Cloudflare KV", "namespace", namespaceID)
+
+		case "s3":
+			s3Config := opts.s3
+			s3Config.AccessKeyID = os.Getenv("S3_ACCESS_KEY_ID")
+			s3Config.SecretAccessKey = os.Getenv("S3_SECRET_ACCESS_KEY")
+			if s3Config.AccessKeyID == "" || s3Config.SecretAccessKey == "" {
+				return nil, fmt.Errorf("S3_ACCESS_KEY_ID and S3_SECRET_ACCESS_KEY are required for the s3 publisher")
+			}
+
+			publisher, err := webhook.NewS3Client(s3Config)
+			if err != nil {
+				return nil, fmt.Errorf("creating S3 publisher: %w", err)
+			}
+
+			publishers = append(publishers, publisher)
+			setupLog.Info("publishing activity to S3", "endpoint", s3Config.Endpoint, "bucket", s3Config.Bucket)
+
+		case "redis":
+			redisConfig := opts.redis
+			redisConfig.Username = os.Getenv("REDIS_USERNAME")
+			redisConfig.Password = os.Getenv("REDIS_PASSWORD")
+
+			publisher := webhook.NewRedisClient(redisConfig)
+			if err := mgr.Add(publisher); err != nil {
+				return nil, fmt.Errorf("adding redis publisher: %w", err)
+			}
+
+			publishers = append(publishers, publisher)
+			setupLog.Info("publishing activity to Redis", "addr", redisConfig.Addr, "channel", redisConfig.Channel)
+
+		case "static":
+			if opts.staticDir == "" {
+				return nil, fmt.Errorf("--static-dir is required for the static publisher")
+			}
+
+			publishers = append(publishers, webhook.NewStaticSiteWriter(opts.staticDir))
+			setupLog.Info("rendering static JSON API", "dir", opts.staticDir)
+
+		default:
+			return nil, fmt.Errorf("unknown publisher %q", name)
+		}
+	}
+
+	return publishers, nil
+}
+
+// documentPublishers returns the publishers that can also host static documents
+func documentPublishers(publishers []webhook.Publisher) []webhook.DocumentPublisher {
+	var documents []webhook.DocumentPublisher
+	for _, publisher := range publishers {
+		if document, ok := publisher.(webhook.DocumentPublisher); ok {
+			documents = append(documents, document)
+		}
+	}
+	return documents
+}
+
+// splitList parses a comma-separated flag value, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
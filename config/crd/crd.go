@@ -0,0 +1,102 @@
+// Package crd embeds the AnimeMonitor CustomResourceDefinition, so tools
+// that work on manifests outside the cluster, such as the backtest
+// subcommand, see the same schema defaults the API server applies.
+package crd
+
+import (
+	_ "embed"
+	"fmt"
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+)
+
+//go:embed weebcast.com_animemonitors.yaml
+var animeMonitors []byte
+
+// schema is the part of an OpenAPI v3 schema needed to apply defaults
+type schema struct {
+	Default    interface{}        `json:"default,omitempty"`
+	Properties map[string]*schema `json:"properties,omitempty"`
+	Items      *schema            `json:"items,omitempty"`
+}
+
+var (
+	schemasOnce sync.Once
+	schemas     map[string]*schema
+	schemasErr  error
+)
+
+// versionSchemas parses the embedded CRD into a schema per served version
+func versionSchemas() (map[string]*schema, error) {
+	schemasOnce.Do(func() {
+		var crd struct {
+			Spec struct {
+				Versions []struct {
+					Name   string `json:"name"`
+					Schema struct {
+						OpenAPIV3Schema *schema `json:"openAPIV3Schema"`
+					} `json:"schema"`
+				} `json:"versions"`
+			} `json:"spec"`
+		}
+		if schemasErr = yaml.Unmarshal(animeMonitors, &crd); schemasErr != nil {
+			schemasErr = fmt.Errorf("parsing AnimeMonitor CRD: %w", schemasErr)
+			return
+		}
+
+		schemas = make(map[string]*schema, len(crd.Spec.Versions))
+		for _, version := range crd.Spec.Versions {
+			schemas[version.Name] = version.Schema.OpenAPIV3Schema
+		}
+	})
+	return schemas, schemasErr
+}
+
+// ApplyDefaults fills the fields an AnimeMonitor object leaves unset with
+// the defaults of its version's schema. As on admission, defaults apply to
+// missing fields of objects that are present, so a partial
+// spec.scoringWeights gets the remaining weights while an absent one stays
+// absent.
+func ApplyDefaults(obj map[string]interface{}) error {
+	schemas, err := versionSchemas()
+	if err != nil {
+		return err
+	}
+
+	apiVersion, _ := obj["apiVersion"].(string)
+	version := apiVersion[strings.LastIndex(apiVersion, "/")+1:]
+	s, ok := schemas[version]
+	if !ok || s == nil {
+		return fmt.Errorf("no AnimeMonitor schema for apiVersion %q", apiVersion)
+	}
+
+	applyDefaults(obj, s)
+	return nil
+}
+
+// applyDefaults walks a JSON value alongside its schema, setting defaults
+// for missing properties and descending into objects and arrays
+func applyDefaults(value interface{}, s *schema) {
+	if s == nil {
+		return
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for name, property := range s.Properties {
+			if _, ok := v[name]; !ok && property != nil && property.Default != nil {
+				v[name] = runtime.DeepCopyJSONValue(property.Default)
+			}
+		}
+		for name, field := range v {
+			applyDefaults(field, s.Properties[name])
+		}
+	case []interface{}:
+		for _, item := range v {
+			applyDefaults(item, s.Items)
+		}
+	}
+}
@@ -3,29 +3,83 @@ package controller
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	weebcastv1alpha1 "github.com/weebcast/weebcast-operator/api/v1alpha1"
+	"github.com/weebcast/weebcast-operator/pkg/history"
 	"github.com/weebcast/weebcast-operator/pkg/mal"
+	"github.com/weebcast/weebcast-operator/pkg/webhook"
 )
 
+// defaultHistorySize is the number of samples kept when spec.historySize is unset
+const defaultHistorySize = 24
+
+// minMomentumInterval is the shortest gap between polls that momentum
+// scoring will turn into a rate
+const minMomentumInterval = time.Minute
+
 // AnimeMonitorReconciler reconciles an AnimeMonitor object
 type AnimeMonitorReconciler struct {
 	client.Client
 	Scheme    *runtime.Scheme
 	MALClient *mal.Client
+
+	// Recorder emits Kubernetes events, e.g. when an anomaly is detected (optional)
+	Recorder record.EventRecorder
+
+	// Publishers receive each monitor's state after a successful reconcile (optional)
+	Publishers []webhook.Publisher
+
+	// HistoryStore keeps every fetched sample for long-term analysis (optional)
+	HistoryStore SampleStore
+
+	// MessageTemplates is the ConfigMap with WeebcastStatus message templates
+	// for monitors that do not name their own (optional)
+	MessageTemplates client.ObjectKey
+
+	// APIReader reads message template ConfigMaps straight from the API
+	// server, so the operator does not cache every ConfigMap in the cluster.
+	// Defaults to the cached Client.
+	APIReader client.Reader
+
+	// templates holds the loadedTemplates of each message template
+	// ConfigMap, keyed by namespace/name
+	templates sync.Map
+
+	// expressions holds one compiledExpression per monitor, keyed by
+	// namespace/name, so an expression is compiled once and replaced when
+	// the monitor's expression changes
+	expressions sync.Map
+}
+
+// SampleStore records samples per monitor and reads them back in time
+// order. It is implemented by *history.Store, and by an in-memory replay
+// during a backtest.
+type SampleStore interface {
+	// Append records a sample for the monitor
+	Append(monitor string, sample history.Sample) error
+
+	// Range calls fn for each of the monitor's samples in [from, to),
+	// oldest first. A zero to means no upper bound.
+	Range(monitor string, from, to time.Time, fn func(history.Sample) error) error
 }
 
 // +kubebuilder:rbac:groups=weebcast.com,resources=animemonitors,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=weebcast.com,resources=animemonitors/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=weebcast.com,resources=animemonitors/finalizers,verbs=update
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get
 
 // Reconcile handles the reconciliation loop for AnimeMonitor resources
 func (r *AnimeMonitorReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -35,9 +89,20 @@ func (r *AnimeMonitorReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	// Fetch the AnimeMonitor instance
 	monitor := &weebcastv1alpha1.AnimeMonitor{}
 	if err := r.Get(ctx, req.NamespacedName, monitor); err != nil {
+		if apierrors.IsNotFound(err) {
+			r.expressions.Delete(req.NamespacedName)
+		}
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
+	// A deleted monitor is only removed from the publishers, never polled
+	if !monitor.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, r.finalizeMonitor(ctx, monitor)
+	}
+	if err := r.ensureFinalizer(ctx, monitor); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	// Keep a copy of the original for patching
 	monitorPatch := client.MergeFrom(monitor.DeepCopy())
 
@@ -76,6 +141,9 @@ func (r *AnimeMonitorReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		return ctrl.Result{}, err
 	}
 
+	// Publish the new state to weebcast.com
+	r.publishActivity(ctx, monitor)
+
 	logger.Info("Successfully reconciled AnimeMonitor",
 		"activityLevel", monitor.Status.ActivityLevel,
 		"weebcastStatus", monitor.Status.WeebcastStatus)
@@ -111,7 +179,8 @@ func (r *AnimeMonitorReconciler) reconcileSpecificAnime(ctx context.Context, mon
 		logger.Info("Could not fetch statistics, using basic data", "error", err)
 	}
 
-	// Update metrics
+	// Update metrics, keeping the previous poll for momentum scoring
+	previousMetrics, previousChecked := monitor.Status.Metrics, monitor.Status.LastChecked
 	monitor.Status.Metrics = weebcastv1alpha1.AnimeActivityMetrics{
 		Score:         anime.Score,
 		ScoredByCount: anime.ScoredBy,
@@ -129,18 +198,24 @@ func (r *AnimeMonitorReconciler) reconcileSpecificAnime(ctx context.Context, mon
 		monitor.Status.Metrics.ActiveUsers = stats.Watching + stats.Completed/10 // Rough estimate
 	}
 
-	// Calculate activity level based on engagement
-	activityScore := calculateActivityScore(monitor.Status.Metrics)
-	previousLevel := monitor.Status.ActivityLevel
-	monitor.Status.ActivityLevel = r.determineActivityLevel(activityScore, monitor.Spec)
-
-	if previousLevel != monitor.Status.ActivityLevel {
-		monitor.Status.LastActivityChange = metav1.Now()
+	// Track the broadcast slot while the anime is airing
+	monitor.Status.Broadcast = nil
+	if anime.Airing && anime.Broadcast.Day != "" && anime.Broadcast.Time != "" {
+		monitor.Status.Broadcast = &weebcastv1alpha1.BroadcastSchedule{
+			Day:      anime.Broadcast.Day,
+			Time:     anime.Broadcast.Time,
+			Timezone: anime.Broadcast.Timezone,
+			Episodes: anime.Episodes,
+		}
+		if anime.Aired.From != nil {
+			premiered := metav1.NewTime(*anime.Aired.From)
+			monitor.Status.Broadcast.Premiered = &premiered
+		}
 	}
 
-	// Set Weebcast status based on activity
-	monitor.Status.WeebcastStatus = r.deriveWeebcastStatus(monitor.Status.ActivityLevel, anime.Title)
-	monitor.Status.LastChecked = metav1.Now()
+	// Calculate activity level based on engagement
+	activityScore := calculateActivityScore(monitor.Status.Metrics, monitor.Spec.ScoringWeights)
+	r.applyActivity(ctx, monitor, anime.Title, activityScore, previousMetrics, previousChecked, time.Now())
 	monitor.Status.Message = fmt.Sprintf("Monitoring '%s' - %d members, %.2f score",
 		anime.Title, anime.Members, anime.Score)
 
@@ -158,114 +233,253 @@ func (r *AnimeMonitorReconciler) reconcileOverallActivity(ctx context.Context, m
 		return fmt.Errorf("fetching overall activity: %w", err)
 	}
 
-	// Get trending anime (top airing by popularity)
-	topAiring, err := r.MALClient.GetTopAiring(ctx, 10)
-	if err != nil {
-		logger.Info("Could not fetch top airing", "error", err)
-	}
-
-	// Get seasonal anime (current season releases)
-	seasonalAnime, err := r.MALClient.GetSeasonNow(ctx, 10)
-	if err != nil {
-		logger.Info("Could not fetch seasonal anime", "error", err)
-	}
-
-	// Update metrics
+	// Update metrics, keeping the previous poll for momentum scoring
+	previousMetrics, previousChecked := monitor.Status.Metrics, monitor.Status.LastChecked
 	monitor.Status.Metrics = weebcastv1alpha1.AnimeActivityMetrics{
 		ActiveUsers: metrics.TotalActiveUsers,
 		Members:     metrics.TotalMembers,
 		Score:       metrics.AverageScore,
 	}
 
-	// Build trending anime list (top airing)
-	monitor.Status.TrendingAnime = make([]weebcastv1alpha1.TrendingAnime, 0, len(topAiring))
-	for _, anime := range topAiring {
-		monitor.Status.TrendingAnime = append(monitor.Status.TrendingAnime, r.buildTrendingEntry(anime))
-	}
-
-	// Build seasonal anime list
-	monitor.Status.SeasonalAnime = make([]weebcastv1alpha1.TrendingAnime, 0, len(seasonalAnime))
-	for _, anime := range seasonalAnime {
-		monitor.Status.SeasonalAnime = append(monitor.Status.SeasonalAnime, r.buildTrendingEntry(anime))
-	}
+	// Refresh the trending (top airing) and seasonal lists, keeping the
+	// previous ones when a fetch fails
+	r.refreshAnimeLists(ctx, monitor, time.Now())
 
 	// Set current season
-	monitor.Status.CurrentSeason = getCurrentSeason()
+	monitor.Status.CurrentSeason = mal.CurrentSeason(time.Now())
 
 	// Calculate overall activity level
-	activityScore := metrics.TotalActiveUsers + (metrics.TotalMembers / 1000)
+	activityScore := calculateOverallScore(monitor.Status.Metrics)
+	r.applyActivity(ctx, monitor, "", activityScore, previousMetrics, previousChecked, time.Now())
+	monitor.Status.Message = fmt.Sprintf("Overall MAL Activity: %d active users across %d members, tracking %d trending + %d seasonal anime",
+		metrics.TotalActiveUsers, metrics.TotalMembers, len(monitor.Status.TrendingAnime), len(monitor.Status.SeasonalAnime))
+
+	return nil
+}
+
+// applyActivity turns a monitor's raw activity score, observed at now, into
+// its level, forecast, anomaly and history status, and returns the score
+// after spec.scoringMode is applied. title names the anime in forecast
+// messages and is empty for overall activity.
+func (r *AnimeMonitorReconciler) applyActivity(ctx context.Context, monitor *weebcastv1alpha1.AnimeMonitor, title string, activityScore int, previousMetrics weebcastv1alpha1.AnimeActivityMetrics, previousChecked metav1.Time, now time.Time) int {
+	activityScore = r.scoreForMode(monitor, activityScore, previousMetrics, previousChecked, now)
 	previousLevel := monitor.Status.ActivityLevel
-	monitor.Status.ActivityLevel = r.determineActivityLevel(activityScore, monitor.Spec)
+	thresholds := r.effectiveThresholds(ctx, monitor, now)
+	monitor.Status.EffectiveThresholds = &thresholds
+	monitor.Status.ActivityLevel = r.transitionLevel(monitor, activityScore, thresholds, now)
+	applyLadder(monitor, activityScore)
 
 	if previousLevel != monitor.Status.ActivityLevel {
-		monitor.Status.LastActivityChange = metav1.Now()
+		monitor.Status.LastActivityChange = metav1.NewTime(now)
 	}
 
-	// Set Weebcast status
-	monitor.Status.WeebcastStatus = r.deriveWeebcastStatus(monitor.Status.ActivityLevel, "")
-	monitor.Status.LastChecked = metav1.Now()
-	monitor.Status.Message = fmt.Sprintf("Overall MAL Activity: %d active users across %d members, tracking %d trending + %d seasonal anime",
-		metrics.TotalActiveUsers, metrics.TotalMembers, len(topAiring), len(seasonalAnime))
+	// Set Weebcast status based on activity
+	r.setForecastMessages(ctx, monitor, title, activityScore, previousMetrics, previousChecked, now)
+	monitor.Status.LastChecked = metav1.NewTime(now)
+	monitor.Status.Forecast = weatherForecast(monitor, activityScore, previousMetrics, previousChecked)
+	r.checkAnomalies(monitor, activityScore)
+	recordHistory(monitor, activityScore)
+	r.storeSample(ctx, monitor, activityScore)
+	r.updateOutlook(ctx, monitor, now)
+	return activityScore
+}
 
-	return nil
+// publishActivity pushes the monitor's current state to every configured
+// publisher. Publish failures are logged but do not fail the reconcile;
+// the next poll will publish again.
+func (r *AnimeMonitorReconciler) publishActivity(ctx context.Context, monitor *weebcastv1alpha1.AnimeMonitor) {
+	if len(r.Publishers) == 0 {
+		return
+	}
+
+	logger := log.FromContext(ctx)
+	key := webhook.ActivityKey(monitor)
+	payload := webhook.NewActivityPayload(monitor)
+
+	for _, publisher := range r.Publishers {
+		if err := publisher.PushActivity(ctx, key, payload); err != nil {
+			logger.Error(err, "Failed to publish activity", "publisher", publisher.Name(), "key", key)
+		}
+	}
+}
+
+// historySize returns the effective spec.historySize
+func historySize(monitor *weebcastv1alpha1.AnimeMonitor) int {
+	if monitor.Spec.HistorySize != nil {
+		return *monitor.Spec.HistorySize
+	}
+	return defaultHistorySize
+}
+
+// recordHistory appends the latest observation to the monitor's history,
+// dropping the oldest samples beyond spec.historySize
+func recordHistory(monitor *weebcastv1alpha1.AnimeMonitor, activityScore int) {
+	size := historySize(monitor)
+	if size <= 0 {
+		monitor.Status.History = nil
+		return
+	}
+
+	history := append(monitor.Status.History, weebcastv1alpha1.ActivitySample{
+		Timestamp:     monitor.Status.LastChecked,
+		Members:       monitor.Status.Metrics.Members,
+		Watching:      monitor.Status.Metrics.WatchingCount,
+		Score:         monitor.Status.Metrics.Score,
+		Rank:          monitor.Status.Metrics.Rank,
+		ActivityScore: activityScore,
+		Level:         monitor.Status.ActivityLevel,
+	})
+	if len(history) > size {
+		history = history[len(history)-size:]
+	}
+	monitor.Status.History = history
+}
+
+// storeSample appends the latest observation to the long-term history store.
+// Store failures are logged but do not fail the reconcile.
+func (r *AnimeMonitorReconciler) storeSample(ctx context.Context, monitor *weebcastv1alpha1.AnimeMonitor, activityScore int) {
+	if r.HistoryStore == nil {
+		return
+	}
+
+	metrics := monitor.Status.Metrics
+	sample := history.Sample{
+		Timestamp:        monitor.Status.LastChecked.Time,
+		ActiveUsers:      metrics.ActiveUsers,
+		WatchingCount:    metrics.WatchingCount,
+		CompletedCount:   metrics.CompletedCount,
+		DroppedCount:     metrics.DroppedCount,
+		PlanToWatchCount: metrics.PlanToWatchCount,
+		Score:            metrics.Score,
+		ScoredByCount:    metrics.ScoredByCount,
+		Rank:             metrics.Rank,
+		Popularity:       metrics.Popularity,
+		Members:          metrics.Members,
+		Favorites:        metrics.Favorites,
+		ActivityScore:    activityScore,
+		Level:            string(monitor.Status.ActivityLevel),
+	}
+
+	name := client.ObjectKeyFromObject(monitor).String()
+	if err := r.HistoryStore.Append(name, sample); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to store sample", "monitor", name)
+	}
+}
+
+// defaultScoringWeights are the Absolute scoring weights used when
+// spec.scoringWeights is unset
+var defaultScoringWeights = weebcastv1alpha1.ScoringWeights{
+	ActiveUsers:        10,
+	Watching:           5,
+	MembersPerPoint:    1000,
+	FavoritesPerPoint:  100,
+	HighScoreBonus:     500, // High-rated anime tend to have more engagement
+	HighScoreThreshold: 8.0,
 }
 
 // calculateActivityScore computes a normalized activity score from metrics
-func calculateActivityScore(metrics weebcastv1alpha1.AnimeActivityMetrics) int {
+func calculateActivityScore(metrics weebcastv1alpha1.AnimeActivityMetrics, weights *weebcastv1alpha1.ScoringWeights) int {
+	if weights == nil {
+		weights = &defaultScoringWeights
+	}
+
 	// Weight different factors to determine activity
 	score := 0
-	score += metrics.ActiveUsers * 10
-	score += metrics.WatchingCount * 5
-	score += metrics.Members / 1000
-	score += metrics.Favorites / 100
-	if metrics.Score > 8.0 {
-		score += 500 // High-rated anime tend to have more engagement
+	score += metrics.ActiveUsers * weights.ActiveUsers
+	score += metrics.WatchingCount * weights.Watching
+	if weights.MembersPerPoint > 0 {
+		score += metrics.Members / weights.MembersPerPoint
+	}
+	if weights.FavoritesPerPoint > 0 {
+		score += metrics.Favorites / weights.FavoritesPerPoint
+	}
+	if metrics.Score > weights.HighScoreThreshold {
+		score += weights.HighScoreBonus
 	}
 	return score
 }
 
-// determineActivityLevel maps an activity score to an activity level
-func (r *AnimeMonitorReconciler) determineActivityLevel(score int, spec weebcastv1alpha1.AnimeMonitorSpec) weebcastv1alpha1.ActivityLevel {
-	highThreshold := spec.HighActivityThreshold
-	mediumThreshold := spec.MediumActivityThreshold
+// calculateOverallScore computes the Absolute activity score of the overall
+// MAL monitor, whose metrics only carry active users and members
+func calculateOverallScore(metrics weebcastv1alpha1.AnimeActivityMetrics) int {
+	return metrics.ActiveUsers + metrics.Members/1000
+}
 
-	if highThreshold == 0 {
-		highThreshold = 1000
-	}
-	if mediumThreshold == 0 {
-		mediumThreshold = 500
+// scoreForMode returns the activity score for the monitor's scoring mode.
+// absolute is the score from the latest totals; previous and since are the
+// metrics and check time of the previous poll, and now the current check.
+func (r *AnimeMonitorReconciler) scoreForMode(monitor *weebcastv1alpha1.AnimeMonitor, absolute int, previous weebcastv1alpha1.AnimeActivityMetrics, since metav1.Time, now time.Time) int {
+	if monitor.Spec.ScoringMode != weebcastv1alpha1.ScoringModeExpression {
+		meta.RemoveStatusCondition(&monitor.Status.Conditions, scoringExpressionConditionType)
 	}
 
-	criticalThreshold := highThreshold * 2
+	switch monitor.Spec.ScoringMode {
+	case weebcastv1alpha1.ScoringModeMomentum:
+		elapsed := now.Sub(since.Time)
+		if since.IsZero() || elapsed < minMomentumInterval {
+			// Too soon to measure a rate (first poll, or a re-reconcile after a
+			// spec change), so keep the last score
+			return lastActivityScore(monitor)
+		}
+		return calculateMomentumScore(previous, monitor.Status.Metrics, elapsed)
+
+	case weebcastv1alpha1.ScoringModeExpression:
+		return r.scoreExpression(monitor, previous, since, now)
 
-	switch {
-	case score >= criticalThreshold:
-		return weebcastv1alpha1.ActivityLevelCritical
-	case score >= highThreshold:
-		return weebcastv1alpha1.ActivityLevelHigh
-	case score >= mediumThreshold:
-		return weebcastv1alpha1.ActivityLevelMedium
 	default:
-		return weebcastv1alpha1.ActivityLevelLow
+		return absolute
+	}
+}
+
+// lastActivityScore returns the most recently recorded activity score
+func lastActivityScore(monitor *weebcastv1alpha1.AnimeMonitor) int {
+	if n := len(monitor.Status.History); n > 0 {
+		return monitor.Status.History[n-1].ActivityScore
+	}
+	return 0
+}
+
+// calculateMomentumScore computes an activity score from the rate of change
+// between two polls, so a breakout show outscores a long-running classic
+// with a larger but static audience
+func calculateMomentumScore(previous, current weebcastv1alpha1.AnimeActivityMetrics, elapsed time.Duration) int {
+	hours := elapsed.Hours()
+	perHour := func(before, after int) float64 {
+		return float64(after-before) / hours
+	}
+
+	score := 0.0
+	score += perHour(previous.Members, current.Members)                 // New members per hour
+	score += perHour(previous.WatchingCount, current.WatchingCount) * 5 // Watching growth per hour
+	score += perHour(previous.ActiveUsers, current.ActiveUsers) * 10
+	score += perHour(previous.Favorites, current.Favorites) * 10
+	if previous.Score > 0 {
+		score += (current.Score - previous.Score) * 1000 // Score movement between polls
+	}
+
+	if score < 0 {
+		return 0 // A shrinking audience is simply quiet
 	}
+	return int(score)
 }
 
-// deriveWeebcastStatus generates a Weebcast weather forecast status message
-func (r *AnimeMonitorReconciler) deriveWeebcastStatus(level weebcastv1alpha1.ActivityLevel, animeName string) string {
-	var statusPrefix string
-	if animeName != "" {
-		statusPrefix = fmt.Sprintf("[%s] ", animeName)
+// determineActivityLevel maps an activity score to an activity level. With a
+// hysteresis percentage, leaving the current level requires the score to fall
+// that far below the level's threshold.
+func (r *AnimeMonitorReconciler) determineActivityLevel(score int, thresholds weebcastv1alpha1.ActivityThresholds, current weebcastv1alpha1.ActivityLevel, hysteresisPercent int) weebcastv1alpha1.ActivityLevel {
+	if hysteresisPercent > 0 {
+		thresholds = withHysteresis(thresholds, current, hysteresisPercent)
 	}
 
-	switch level {
-	case weebcastv1alpha1.ActivityLevelCritical:
-		return statusPrefix + "🌀 TYPHOON ALERT! A category 5 weeb storm is making landfall! Extreme anime energy detected across all sectors. This is not a drill - expect maximum hype levels, server strain, and spontaneous waifu debates. All weebs advised to secure their watchlists!"
-	case weebcastv1alpha1.ActivityLevelHigh:
-		return statusPrefix + "⛈️ STORM WARNING! A massive weeb front is moving in! Heavy anime discussions expected with a high chance of trending hashtags. Take shelter in your favorite streaming site - it's going to be a wild one!"
-	case weebcastv1alpha1.ActivityLevelMedium:
-		return statusPrefix + "⛅ Partly cloudy conditions in the weeb-o-sphere. Moderate anime activity detected with occasional bursts of excitement. Good conditions for casual binge-watching. Keep an umbrella ready for surprise episode drops!"
+	switch {
+	case score >= thresholds.Critical:
+		return weebcastv1alpha1.ActivityLevelCritical
+	case score >= thresholds.High:
+		return weebcastv1alpha1.ActivityLevelHigh
+	case score >= thresholds.Medium:
+		return weebcastv1alpha1.ActivityLevelMedium
 	default:
-		return statusPrefix + "☀️ Clear skies across the anime landscape! A peaceful day in the weeb-o-sphere. Perfect weather for catching up on your backlog or discovering hidden gems. Enjoy the calm before the next seasonal storm!"
+		return weebcastv1alpha1.ActivityLevelLow
 	}
 }
 
@@ -317,30 +531,11 @@ func (r *AnimeMonitorReconciler) buildTrendingEntry(anime mal.AnimeData) weebcas
 	}
 }
 
-// getCurrentSeason returns the current anime season (e.g., "Winter 2025")
-func getCurrentSeason() string {
-	now := time.Now()
-	month := now.Month()
-	year := now.Year()
-
-	var season string
-	switch {
-	case month >= 1 && month <= 3:
-		season = "Winter"
-	case month >= 4 && month <= 6:
-		season = "Spring"
-	case month >= 7 && month <= 9:
-		season = "Summer"
-	default:
-		season = "Fall"
-	}
-
-	return fmt.Sprintf("%s %d", season, year)
-}
-
 // SetupWithManager sets up the controller with the Manager
 func (r *AnimeMonitorReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&weebcastv1alpha1.AnimeMonitor{}).
+		// Status updates must not trigger a new poll; polling is driven by RequeueAfter
+		WithEventFilter(predicate.GenerationChangedPredicate{}).
 		Complete(r)
 }
@@ -0,0 +1,195 @@
+package controller
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	weebcastv1alpha1 "github.com/weebcast/weebcast-operator/api/v1alpha1"
+)
+
+const (
+	// anomalyConditionType is the condition raised when a metric deviates from its baseline
+	anomalyConditionType = "Anomaly"
+
+	// defaultZScoreThreshold is used when spec.anomalyDetection.zScoreThreshold is unset
+	defaultZScoreThreshold = 3.0
+
+	// defaultAnomalyMinSamples is used when spec.anomalyDetection.minSamples is unset
+	defaultAnomalyMinSamples = 6
+)
+
+// metricSeries is a watched metric's history and its current value
+type metricSeries struct {
+	name    string
+	values  []float64
+	current float64
+}
+
+// anomalySeries returns each watched metric's history (oldest first) and its
+// current value. Members only ever grow, so their per-poll growth is watched
+// instead of the total.
+func anomalySeries(history []weebcastv1alpha1.ActivitySample, metrics weebcastv1alpha1.AnimeActivityMetrics, activityScore int) []metricSeries {
+	var scores, watching, ratings, growth []float64
+	for i, sample := range history {
+		scores = append(scores, float64(sample.ActivityScore))
+		watching = append(watching, float64(sample.Watching))
+		ratings = append(ratings, sample.Score)
+		if i > 0 {
+			growth = append(growth, float64(sample.Members-history[i-1].Members))
+		}
+	}
+
+	series := []metricSeries{
+		{name: "activityScore", values: scores, current: float64(activityScore)},
+		{name: "watching", values: watching, current: float64(metrics.WatchingCount)},
+		{name: "score", values: ratings, current: metrics.Score},
+	}
+	if n := len(history); n > 0 {
+		series = append(series, metricSeries{
+			name:    "memberGrowth",
+			values:  growth,
+			current: float64(metrics.Members - history[n-1].Members),
+		})
+	}
+	return series
+}
+
+// detectAnomalies compares the current observation with the rolling mean
+// and standard deviation of each metric over status.history, which must not
+// yet include the current sample. It returns the metrics whose z-score
+// exceeds the threshold and whether there was enough history to decide.
+func detectAnomalies(monitor *weebcastv1alpha1.AnimeMonitor, activityScore int) ([]weebcastv1alpha1.MetricAnomaly, bool) {
+	spec := monitor.Spec.AnomalyDetection
+	threshold := spec.ZScoreThreshold
+	if threshold <= 0 {
+		threshold = defaultZScoreThreshold
+	}
+
+	if len(monitor.Status.History) < minAnomalySamples(monitor) {
+		return nil, false
+	}
+
+	var anomalies []weebcastv1alpha1.MetricAnomaly
+	for _, s := range anomalySeries(monitor.Status.History, monitor.Status.Metrics, activityScore) {
+		if len(s.values) < 2 {
+			continue
+		}
+
+		mean, stdDev := meanStdDev(s.values)
+		if stdDev == 0 {
+			// A perfectly flat series has no meaningful z-score
+			continue
+		}
+
+		z := (s.current - mean) / stdDev
+		if math.Abs(z) >= threshold {
+			anomalies = append(anomalies, weebcastv1alpha1.MetricAnomaly{
+				Metric: s.name,
+				Value:  s.current,
+				Mean:   round2(mean),
+				StdDev: round2(stdDev),
+				ZScore: round2(z),
+			})
+		}
+	}
+
+	return anomalies, true
+}
+
+// meanStdDev returns the mean and population standard deviation of values
+func meanStdDev(values []float64) (float64, float64) {
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(len(values))
+
+	var squares float64
+	for _, v := range values {
+		squares += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(squares / float64(len(values)))
+}
+
+// round2 rounds to two decimals to keep status readable
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+// checkAnomalies runs anomaly detection for the monitor, records the flagged
+// metrics in status, sets the Anomaly condition and emits an event when the
+// monitor becomes anomalous. It must be called before recordHistory.
+func (r *AnimeMonitorReconciler) checkAnomalies(monitor *weebcastv1alpha1.AnimeMonitor, activityScore int) {
+	if monitor.Spec.AnomalyDetection == nil {
+		monitor.Status.Anomalies = nil
+		meta.RemoveStatusCondition(&monitor.Status.Conditions, anomalyConditionType)
+		return
+	}
+
+	// Monitors created before the CRD rejected this would otherwise wait forever
+	if minSamples, size := minAnomalySamples(monitor), historySize(monitor); minSamples > size {
+		monitor.Status.Anomalies = nil
+		meta.SetStatusCondition(&monitor.Status.Conditions, metav1.Condition{
+			Type:    anomalyConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  "MinSamplesExceedHistory",
+			Message: fmt.Sprintf("anomalyDetection.minSamples (%d) exceeds historySize (%d), so detection can never start", minSamples, size),
+		})
+		return
+	}
+
+	anomalies, ok := detectAnomalies(monitor, activityScore)
+	monitor.Status.Anomalies = anomalies
+
+	if !ok {
+		meta.SetStatusCondition(&monitor.Status.Conditions, metav1.Condition{
+			Type:    anomalyConditionType,
+			Status:  metav1.ConditionUnknown,
+			Reason:  "InsufficientHistory",
+			Message: fmt.Sprintf("Waiting for %d history samples, have %d", minAnomalySamples(monitor), len(monitor.Status.History)),
+		})
+		return
+	}
+
+	if len(anomalies) == 0 {
+		meta.SetStatusCondition(&monitor.Status.Conditions, metav1.Condition{
+			Type:    anomalyConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  "WithinBaseline",
+			Message: "All metrics are within their rolling baseline",
+		})
+		return
+	}
+
+	descriptions := make([]string, 0, len(anomalies))
+	for _, anomaly := range anomalies {
+		descriptions = append(descriptions, fmt.Sprintf("%s=%g (mean %g, z=%+.2f)",
+			anomaly.Metric, anomaly.Value, anomaly.Mean, anomaly.ZScore))
+	}
+	message := "Unusual " + strings.Join(descriptions, ", ")
+
+	wasAnomalous := meta.IsStatusConditionTrue(monitor.Status.Conditions, anomalyConditionType)
+	meta.SetStatusCondition(&monitor.Status.Conditions, metav1.Condition{
+		Type:    anomalyConditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  "MetricDeviation",
+		Message: message,
+	})
+
+	if !wasAnomalous && r.Recorder != nil {
+		r.Recorder.Event(monitor, corev1.EventTypeWarning, "AnomalyDetected", message)
+	}
+}
+
+// minAnomalySamples returns the effective spec.anomalyDetection.minSamples
+func minAnomalySamples(monitor *weebcastv1alpha1.AnimeMonitor) int {
+	if monitor.Spec.AnomalyDetection.MinSamples > 0 {
+		return monitor.Spec.AnomalyDetection.MinSamples
+	}
+	return defaultAnomalyMinSamples
+}
@@ -0,0 +1,88 @@
+package controller
+
+import (
+	"testing"
+
+	weebcastv1alpha1 "github.com/weebcast/weebcast-operator/api/v1alpha1"
+)
+
+// scoreHistory returns status.history samples with the given activity
+// scores and every other metric flat
+func scoreHistory(scores ...int) []weebcastv1alpha1.ActivitySample {
+	samples := make([]weebcastv1alpha1.ActivitySample, len(scores))
+	for i, score := range scores {
+		samples[i] = weebcastv1alpha1.ActivitySample{ActivityScore: score, Members: 1000, Watching: 50, Score: 8}
+	}
+	return samples
+}
+
+func TestDetectAnomalies(t *testing.T) {
+	// Mean 100, standard deviation 10
+	swinging := scoreHistory(90, 110, 90, 110, 90, 110)
+
+	tests := []struct {
+		name      string
+		threshold float64
+		history   []weebcastv1alpha1.ActivitySample
+		members   int
+		score     int
+		wantOK    bool
+		// want lists the flagged metrics with their z-scores
+		want map[string]float64
+	}{
+		{name: "too little history", threshold: 2, history: swinging[:5], score: 500},
+		{name: "at the threshold", threshold: 2, history: swinging, score: 120, wantOK: true, want: map[string]float64{"activityScore": 2}},
+		{name: "just inside the threshold", threshold: 2, history: swinging, score: 119, wantOK: true},
+		{name: "below the mean", threshold: 2, history: swinging, score: 80, wantOK: true, want: map[string]float64{"activityScore": -2}},
+		{name: "default threshold", history: swinging, score: 130, wantOK: true, want: map[string]float64{"activityScore": 3}},
+		{name: "inside the default threshold", history: swinging, score: 129, wantOK: true},
+		{name: "flat series are skipped", threshold: 2, history: scoreHistory(100, 100, 100, 100, 100, 100), score: 1000, wantOK: true},
+		{
+			name:      "member growth",
+			threshold: 3,
+			history: func() []weebcastv1alpha1.ActivitySample {
+				// Growth of 10, 20, 10, 20, 10 per poll: mean 14, standard deviation 4.9
+				history := scoreHistory(100, 100, 100, 100, 100, 100)
+				for i, members := range []int{1000, 1010, 1030, 1040, 1060, 1070} {
+					history[i].Members = members
+				}
+				return history
+			}(),
+			members: 1110,
+			score:   100,
+			wantOK:  true,
+			want:    map[string]float64{"memberGrowth": 5.31},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			members := tt.members
+			if members == 0 {
+				members = 1000
+			}
+			monitor := &weebcastv1alpha1.AnimeMonitor{
+				Spec: weebcastv1alpha1.AnimeMonitorSpec{
+					AnomalyDetection: &weebcastv1alpha1.AnomalyDetectionSpec{ZScoreThreshold: tt.threshold, MinSamples: 6},
+				},
+				Status: weebcastv1alpha1.AnimeMonitorStatus{
+					History: tt.history,
+					Metrics: weebcastv1alpha1.AnimeActivityMetrics{Members: members, WatchingCount: 50, Score: 8},
+				},
+			}
+
+			anomalies, ok := detectAnomalies(monitor, tt.score)
+			if ok != tt.wantOK {
+				t.Fatalf("detectAnomalies() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if len(anomalies) != len(tt.want) {
+				t.Fatalf("detectAnomalies() = %+v, want %v", anomalies, tt.want)
+			}
+			for _, anomaly := range anomalies {
+				if z, ok := tt.want[anomaly.Metric]; !ok || anomaly.ZScore != z {
+					t.Errorf("%s z-score = %v, want %v", anomaly.Metric, anomaly.ZScore, tt.want)
+				}
+			}
+		})
+	}
+}
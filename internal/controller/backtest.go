@@ -0,0 +1,193 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	weebcastv1alpha1 "github.com/weebcast/weebcast-operator/api/v1alpha1"
+	"github.com/weebcast/weebcast-operator/pkg/history"
+)
+
+// BacktestReport compares the levels a monitor's settings would have
+// produced over recorded history with the levels actually recorded
+type BacktestReport struct {
+	Monitor string    `json:"monitor"`
+	Samples int       `json:"samples"`
+	From    time.Time `json:"from"`
+	To      time.Time `json:"to"`
+
+	// Candidate is the outcome of replaying the history with the monitor's spec
+	Candidate BacktestSummary `json:"candidate"`
+
+	// Recorded is the outcome the operator actually recorded
+	Recorded BacktestSummary `json:"recorded"`
+
+	// Transitions lists every level change of the candidate replay
+	Transitions []BacktestTransition `json:"transitions"`
+}
+
+// BacktestSummary counts level changes and time spent per level
+type BacktestSummary struct {
+	// Transitions is the number of level changes, each of which publishes a
+	// feed entry and a Redis notification
+	Transitions int `json:"transitions"`
+
+	// Notifications is the number of rises into High or Critical, the
+	// alerts spec.notifyOnHighActivity is meant for
+	Notifications int `json:"notifications"`
+
+	// TimeInLevel is the time spent at each level
+	TimeInLevel map[weebcastv1alpha1.ActivityLevel]time.Duration `json:"timeInLevel"`
+
+	// FinalLevel is the level after the last sample
+	FinalLevel weebcastv1alpha1.ActivityLevel `json:"finalLevel"`
+}
+
+// BacktestTransition is a level change during a replay
+type BacktestTransition struct {
+	Time  time.Time                      `json:"time"`
+	From  weebcastv1alpha1.ActivityLevel `json:"from"`
+	To    weebcastv1alpha1.ActivityLevel `json:"to"`
+	Score int                            `json:"score"`
+}
+
+// Backtest replays recorded samples, oldest first, through the same pipeline
+// the reconciler runs on every poll, with the monitor's spec in place of the
+// settings the samples were recorded under. Replayed samples are kept in
+// memory in place of the history store, so Auto thresholds are calibrated
+// from the replayed samples within the configured window.
+func Backtest(ctx context.Context, monitor *weebcastv1alpha1.AnimeMonitor, samples []history.Sample) (*BacktestReport, error) {
+	if len(samples) == 0 {
+		return nil, fmt.Errorf("no samples to replay")
+	}
+	sort.SliceStable(samples, func(i, j int) bool {
+		return samples[i].Timestamp.Before(samples[j].Timestamp)
+	})
+
+	r := &AnimeMonitorReconciler{HistoryStore: &replayStore{}}
+	sim := monitor.DeepCopy()
+	sim.Status = weebcastv1alpha1.AnimeMonitorStatus{}
+
+	// Neither affects levels: the outlook would be projected again for every
+	// sample, and there is no cluster to read message templates from
+	sim.Spec.Outlook = nil
+	sim.Spec.MessageTemplatesConfigMap = ""
+
+	report := &BacktestReport{
+		Monitor: fmt.Sprintf("%s/%s", monitor.Namespace, monitor.Name),
+		Samples: len(samples),
+		From:    samples[0].Timestamp,
+		To:      samples[len(samples)-1].Timestamp,
+	}
+	candidate := newLevelTally()
+	recorded := newLevelTally()
+
+	for _, sample := range samples {
+		now := sample.Timestamp
+		previousMetrics, previousChecked := sim.Status.Metrics, sim.Status.LastChecked
+		sim.Status.Metrics = sampleMetrics(sample)
+
+		activityScore := calculateActivityScore(sim.Status.Metrics, sim.Spec.ScoringWeights)
+		if sim.Spec.AnimeID == 0 {
+			activityScore = calculateOverallScore(sim.Status.Metrics)
+		}
+		previousLevel := sim.Status.ActivityLevel
+		activityScore = r.applyActivity(ctx, sim, sim.Spec.AnimeName, activityScore, previousMetrics, previousChecked, now)
+
+		if candidate.observe(now, sim.Status.ActivityLevel) {
+			report.Transitions = append(report.Transitions, BacktestTransition{
+				Time:  now,
+				From:  previousLevel,
+				To:    sim.Status.ActivityLevel,
+				Score: activityScore,
+			})
+		}
+		if sample.Level != "" {
+			recorded.observe(now, weebcastv1alpha1.ActivityLevel(sample.Level))
+		}
+	}
+
+	report.Candidate = candidate.summary
+	report.Recorded = recorded.summary
+	return report, nil
+}
+
+// replayStore is the in-memory SampleStore of a backtest. It holds the
+// samples of the one replayed monitor in the order they were appended.
+type replayStore struct {
+	samples []history.Sample
+}
+
+// Append records a replayed sample
+func (s *replayStore) Append(_ string, sample history.Sample) error {
+	s.samples = append(s.samples, sample)
+	return nil
+}
+
+// Range calls fn for each replayed sample in [from, to)
+func (s *replayStore) Range(_ string, from, to time.Time, fn func(history.Sample) error) error {
+	first := sort.Search(len(s.samples), func(i int) bool {
+		return !s.samples[i].Timestamp.Before(from)
+	})
+	for _, sample := range s.samples[first:] {
+		if !to.IsZero() && !sample.Timestamp.Before(to) {
+			break
+		}
+		if err := fn(sample); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sampleMetrics converts a stored sample back into activity metrics
+func sampleMetrics(sample history.Sample) weebcastv1alpha1.AnimeActivityMetrics {
+	return weebcastv1alpha1.AnimeActivityMetrics{
+		ActiveUsers:      sample.ActiveUsers,
+		WatchingCount:    sample.WatchingCount,
+		CompletedCount:   sample.CompletedCount,
+		DroppedCount:     sample.DroppedCount,
+		PlanToWatchCount: sample.PlanToWatchCount,
+		Score:            sample.Score,
+		ScoredByCount:    sample.ScoredByCount,
+		Rank:             sample.Rank,
+		Popularity:       sample.Popularity,
+		Members:          sample.Members,
+		Favorites:        sample.Favorites,
+	}
+}
+
+// levelTally accumulates a BacktestSummary from a sequence of levels
+type levelTally struct {
+	summary BacktestSummary
+	since   time.Time
+}
+
+// newLevelTally creates an empty tally
+func newLevelTally() *levelTally {
+	return &levelTally{
+		summary: BacktestSummary{TimeInLevel: make(map[weebcastv1alpha1.ActivityLevel]time.Duration)},
+	}
+}
+
+// observe records the level at a point in time, crediting the time since the
+// previous observation to the previous level, and reports whether it changed
+func (t *levelTally) observe(now time.Time, level weebcastv1alpha1.ActivityLevel) bool {
+	previous := t.summary.FinalLevel
+	if previous != "" {
+		t.summary.TimeInLevel[previous] += now.Sub(t.since)
+	}
+	t.since = now
+	t.summary.FinalLevel = level
+
+	if previous == "" || previous == level {
+		return false
+	}
+	t.summary.Transitions++
+	if level.Rank() > previous.Rank() && level.Rank() >= weebcastv1alpha1.ActivityLevelHigh.Rank() {
+		t.summary.Notifications++
+	}
+	return true
+}
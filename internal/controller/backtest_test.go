@@ -0,0 +1,142 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	weebcastv1alpha1 "github.com/weebcast/weebcast-operator/api/v1alpha1"
+	"github.com/weebcast/weebcast-operator/pkg/history"
+)
+
+var backtestStart = time.Date(2026, 10, 5, 0, 0, 0, 0, time.UTC)
+
+// overallSamples returns hourly samples of an overall monitor, whose
+// activity score is its active user count
+func overallSamples(scores ...int) []history.Sample {
+	samples := make([]history.Sample, len(scores))
+	for i, score := range scores {
+		samples[i] = history.Sample{
+			Timestamp:   backtestStart.Add(time.Duration(i) * time.Hour),
+			ActiveUsers: score,
+		}
+	}
+	return samples
+}
+
+func TestBacktest(t *testing.T) {
+	scores := []int{100, 600, 700, 1200, 1500, 300, 2500, 2600}
+
+	tests := []struct {
+		name string
+		spec weebcastv1alpha1.AnimeMonitorSpec
+		want BacktestSummary
+		// transitions lists the levels entered, in order
+		transitions []weebcastv1alpha1.ActivityLevel
+	}{
+		{
+			name: "manual thresholds",
+			spec: weebcastv1alpha1.AnimeMonitorSpec{
+				MediumActivityThreshold: 500,
+				HighActivityThreshold:   1000,
+			},
+			want: BacktestSummary{
+				Transitions:   4,
+				Notifications: 2,
+				TimeInLevel: map[weebcastv1alpha1.ActivityLevel]time.Duration{
+					low:      2 * time.Hour,
+					medium:   2 * time.Hour,
+					high:     2 * time.Hour,
+					critical: time.Hour,
+				},
+				FinalLevel: critical,
+			},
+			transitions: []weebcastv1alpha1.ActivityLevel{medium, high, low, critical},
+		},
+		{
+			name: "level ladder",
+			spec: weebcastv1alpha1.AnimeMonitorSpec{
+				MediumActivityThreshold: 500,
+				HighActivityThreshold:   1000,
+				LevelLadder: []weebcastv1alpha1.LevelStep{
+					{Name: "Calm", Threshold: 0, MapsTo: low},
+					{Name: "Gale", Threshold: 600, MapsTo: medium},
+					{Name: "Storm", Threshold: 1400, MapsTo: high},
+					{Name: "Typhoon", Threshold: 2400, MapsTo: critical},
+				},
+			},
+			want: BacktestSummary{
+				Transitions:   4,
+				Notifications: 2,
+				TimeInLevel: map[weebcastv1alpha1.ActivityLevel]time.Duration{
+					low:      2 * time.Hour,
+					medium:   3 * time.Hour,
+					high:     time.Hour,
+					critical: time.Hour,
+				},
+				FinalLevel: critical,
+			},
+			transitions: []weebcastv1alpha1.ActivityLevel{medium, high, low, critical},
+		},
+		{
+			name: "dwell time holds short-lived levels",
+			spec: weebcastv1alpha1.AnimeMonitorSpec{
+				MediumActivityThreshold: 500,
+				HighActivityThreshold:   1000,
+				LevelTransitions: &weebcastv1alpha1.LevelTransitionSpec{
+					MinDwell: &metav1.Duration{Duration: time.Hour},
+				},
+			},
+			// Each change lands one sample late, and the one-hour dip to Low
+			// is never committed
+			want: BacktestSummary{
+				Transitions:   3,
+				Notifications: 2,
+				TimeInLevel: map[weebcastv1alpha1.ActivityLevel]time.Duration{
+					low:    2 * time.Hour,
+					medium: 2 * time.Hour,
+					high:   3 * time.Hour,
+				},
+				FinalLevel: critical,
+			},
+			transitions: []weebcastv1alpha1.ActivityLevel{medium, high, critical},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			monitor := &weebcastv1alpha1.AnimeMonitor{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "mal-overall-activity"},
+				Spec:       tt.spec,
+			}
+
+			report, err := Backtest(context.Background(), monitor, overallSamples(scores...))
+			if err != nil {
+				t.Fatalf("Backtest() error = %v", err)
+			}
+
+			got := report.Candidate
+			if got.Transitions != tt.want.Transitions || got.Notifications != tt.want.Notifications || got.FinalLevel != tt.want.FinalLevel {
+				t.Errorf("summary = %d transitions, %d notifications, final %s; want %d, %d, %s",
+					got.Transitions, got.Notifications, got.FinalLevel,
+					tt.want.Transitions, tt.want.Notifications, tt.want.FinalLevel)
+			}
+			for _, level := range []weebcastv1alpha1.ActivityLevel{low, medium, high, critical} {
+				if got.TimeInLevel[level] != tt.want.TimeInLevel[level] {
+					t.Errorf("time in %s = %s, want %s", level, got.TimeInLevel[level], tt.want.TimeInLevel[level])
+				}
+			}
+
+			if len(report.Transitions) != len(tt.transitions) {
+				t.Fatalf("got %d transitions, want %d: %+v", len(report.Transitions), len(tt.transitions), report.Transitions)
+			}
+			for i, transition := range report.Transitions {
+				if transition.To != tt.transitions[i] {
+					t.Errorf("transition %d to %s, want %s", i, transition.To, tt.transitions[i])
+				}
+			}
+		})
+	}
+}
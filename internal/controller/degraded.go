@@ -0,0 +1,79 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	weebcastv1alpha1 "github.com/weebcast/weebcast-operator/api/v1alpha1"
+	"github.com/weebcast/weebcast-operator/pkg/mal"
+)
+
+// degradedConditionType reports whether any of the overall monitor's list
+// sources failed on the last check
+const degradedConditionType = "Degraded"
+
+// animeList is one of the overall monitor's anime lists and the MAL source
+// it is fetched from
+type animeList struct {
+	source  string
+	fetch   func(ctx context.Context, limit int) ([]mal.AnimeData, error)
+	entries *[]weebcastv1alpha1.TrendingAnime
+	updated **metav1.Time
+}
+
+// refreshAnimeLists fetches each of the overall monitor's anime lists. A
+// list whose fetch fails keeps its last-known-good entries and update time,
+// so it reads as stale rather than empty, and the failed sources are named
+// in the Degraded condition.
+func (r *AnimeMonitorReconciler) refreshAnimeLists(ctx context.Context, monitor *weebcastv1alpha1.AnimeMonitor, now time.Time) {
+	logger := log.FromContext(ctx)
+	lists := []animeList{
+		{"top airing", r.MALClient.GetTopAiring, &monitor.Status.TrendingAnime, &monitor.Status.TrendingAnimeUpdated},
+		{"seasonal", r.MALClient.GetSeasonNow, &monitor.Status.SeasonalAnime, &monitor.Status.SeasonalAnimeUpdated},
+	}
+
+	var failures []string
+	for _, list := range lists {
+		anime, err := list.fetch(ctx, 10)
+		if err != nil {
+			logger.Info("Could not fetch anime list, keeping the previous one", "source", list.source, "error", err)
+			failure := fmt.Sprintf("%s: %v", list.source, err)
+			if updated := *list.updated; updated != nil {
+				failure += fmt.Sprintf(" (keeping list from %s)", updated.UTC().Format(time.RFC3339))
+			}
+			failures = append(failures, failure)
+			continue
+		}
+
+		entries := make([]weebcastv1alpha1.TrendingAnime, 0, len(anime))
+		for _, a := range anime {
+			entries = append(entries, r.buildTrendingEntry(a))
+		}
+		*list.entries = entries
+		updated := metav1.NewTime(now)
+		*list.updated = &updated
+	}
+
+	if len(failures) == 0 {
+		meta.SetStatusCondition(&monitor.Status.Conditions, metav1.Condition{
+			Type:    degradedConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  "AllSourcesFetched",
+			Message: "All anime lists were fetched on the last check",
+		})
+		return
+	}
+
+	meta.SetStatusCondition(&monitor.Status.Conditions, metav1.Condition{
+		Type:    degradedConditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  "FetchFailed",
+		Message: "Could not fetch " + strings.Join(failures, "; "),
+	})
+}
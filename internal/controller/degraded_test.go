@@ -0,0 +1,126 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	weebcastv1alpha1 "github.com/weebcast/weebcast-operator/api/v1alpha1"
+	"github.com/weebcast/weebcast-operator/pkg/mal"
+)
+
+func TestRefreshAnimeLists(t *testing.T) {
+	// topAiring and seasonal are the titles each Jikan list returns, or
+	// empty to answer 500
+	var topAiring, seasonal string
+	jikan := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		title := seasonal
+		if r.URL.Path == "/top/anime" {
+			title = topAiring
+		}
+		if title == "" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprintf(w, `{"data":[{"mal_id":1,"title":%q,"members":600000}]}`, title)
+	}))
+	defer jikan.Close()
+
+	r := &AnimeMonitorReconciler{MALClient: mal.NewClient().WithBaseURL(jikan.URL)}
+	monitor := &weebcastv1alpha1.AnimeMonitor{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "mal-overall-activity"},
+	}
+
+	steps := []struct {
+		name                string
+		topAiring           string
+		seasonal            string
+		wantTopAiring       string
+		wantSeasonal        string
+		wantTopUpdated      time.Duration
+		wantSeasonalUpdated time.Duration
+		wantDegraded        metav1.ConditionStatus
+		wantMessage         string
+	}{
+		{
+			name:         "nothing fetched yet",
+			seasonal:     "Frieren",
+			wantSeasonal: "Frieren",
+			wantDegraded: metav1.ConditionTrue,
+			wantMessage:  "Could not fetch top airing: unexpected status code: 500",
+		},
+		{
+			name:                "all sources fetched",
+			topAiring:           "Dandadan",
+			seasonal:            "Frieren",
+			wantTopAiring:       "Dandadan",
+			wantSeasonal:        "Frieren",
+			wantTopUpdated:      time.Hour,
+			wantSeasonalUpdated: time.Hour,
+			wantDegraded:        metav1.ConditionFalse,
+		},
+		{
+			name:                "failed list keeps the last good one",
+			seasonal:            "Apothecary Diaries",
+			wantTopAiring:       "Dandadan",
+			wantSeasonal:        "Apothecary Diaries",
+			wantTopUpdated:      time.Hour,
+			wantSeasonalUpdated: 2 * time.Hour,
+			wantDegraded:        metav1.ConditionTrue,
+			wantMessage:         "Could not fetch top airing: unexpected status code: 500 (keeping list from 2026-10-05T01:00:00Z)",
+		},
+		{
+			name:                "both failed",
+			wantTopAiring:       "Dandadan",
+			wantSeasonal:        "Apothecary Diaries",
+			wantTopUpdated:      time.Hour,
+			wantSeasonalUpdated: 2 * time.Hour,
+			wantDegraded:        metav1.ConditionTrue,
+			wantMessage:         "; seasonal: unexpected status code: 500 (keeping list from 2026-10-05T02:00:00Z)",
+		},
+		{
+			name:                "recovered",
+			topAiring:           "Gachiakuta",
+			seasonal:            "Apothecary Diaries",
+			wantTopAiring:       "Gachiakuta",
+			wantSeasonal:        "Apothecary Diaries",
+			wantTopUpdated:      4 * time.Hour,
+			wantSeasonalUpdated: 4 * time.Hour,
+			wantDegraded:        metav1.ConditionFalse,
+		},
+	}
+
+	for i, step := range steps {
+		topAiring, seasonal = step.topAiring, step.seasonal
+		r.refreshAnimeLists(context.Background(), monitor, backtestStart.Add(time.Duration(i)*time.Hour))
+
+		checkList := func(source string, entries []weebcastv1alpha1.TrendingAnime, updated *metav1.Time, want string, wantUpdated time.Duration) {
+			if want == "" {
+				if len(entries) != 0 || updated != nil {
+					t.Errorf("%s: %s list = %+v updated %v, want none", step.name, source, entries, updated)
+				}
+				return
+			}
+			if len(entries) != 1 || entries[0].Title != want {
+				t.Errorf("%s: %s list = %+v, want %s", step.name, source, entries, want)
+			}
+			if updated == nil || !updated.Time.Equal(backtestStart.Add(wantUpdated)) {
+				t.Errorf("%s: %s list updated %v, want %s", step.name, source, updated, backtestStart.Add(wantUpdated))
+			}
+		}
+		checkList("top airing", monitor.Status.TrendingAnime, monitor.Status.TrendingAnimeUpdated, step.wantTopAiring, step.wantTopUpdated)
+		checkList("seasonal", monitor.Status.SeasonalAnime, monitor.Status.SeasonalAnimeUpdated, step.wantSeasonal, step.wantSeasonalUpdated)
+
+		condition := meta.FindStatusCondition(monitor.Status.Conditions, degradedConditionType)
+		if condition == nil || condition.Status != step.wantDegraded || !strings.Contains(condition.Message, step.wantMessage) {
+			t.Errorf("%s: Degraded condition = %+v, want %s with %q", step.name, condition, step.wantDegraded, step.wantMessage)
+		}
+	}
+}
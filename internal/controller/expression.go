@@ -0,0 +1,141 @@
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	weebcastv1alpha1 "github.com/weebcast/weebcast-operator/api/v1alpha1"
+	"github.com/weebcast/weebcast-operator/pkg/scoring"
+)
+
+// scoringExpressionConditionType reports whether spec.scoringExpression evaluated
+const scoringExpressionConditionType = "ScoringExpression"
+
+// compiledExpression is a monitor's compiled scoring expression
+type compiledExpression struct {
+	source     string
+	expression *scoring.Expression
+}
+
+// compileExpression returns the monitor's compiled scoring expression,
+// compiling it when it is new or has changed
+func (r *AnimeMonitorReconciler) compileExpression(monitor *weebcastv1alpha1.AnimeMonitor) (*scoring.Expression, error) {
+	key := client.ObjectKeyFromObject(monitor)
+	source := monitor.Spec.ScoringExpression
+	if cached, ok := r.expressions.Load(key); ok {
+		if compiled := cached.(*compiledExpression); compiled.source == source {
+			return compiled.expression, nil
+		}
+	}
+
+	expression, err := scoring.Compile(source)
+	if err != nil {
+		r.expressions.Delete(key)
+		return nil, err
+	}
+	r.expressions.Store(key, &compiledExpression{source: source, expression: expression})
+	return expression, nil
+}
+
+// scoreExpression evaluates spec.scoringExpression. Failures keep the last
+// score and are reported through the ScoringExpression condition instead of
+// failing the reconcile.
+func (r *AnimeMonitorReconciler) scoreExpression(monitor *weebcastv1alpha1.AnimeMonitor, previous weebcastv1alpha1.AnimeActivityMetrics, since metav1.Time, now time.Time) int {
+	fail := func(reason string, err error) int {
+		meta.SetStatusCondition(&monitor.Status.Conditions, metav1.Condition{
+			Type:    scoringExpressionConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  reason,
+			Message: fmt.Sprintf("%v; keeping the last activity score", err),
+		})
+		return lastActivityScore(monitor)
+	}
+
+	expression, err := r.compileExpression(monitor)
+	if err != nil {
+		return fail("CompileFailed", err)
+	}
+
+	score, err := expression.Eval(expressionInput(monitor, previous, since, now))
+	if err != nil {
+		return fail("EvaluationFailed", err)
+	}
+
+	meta.SetStatusCondition(&monitor.Status.Conditions, metav1.Condition{
+		Type:    scoringExpressionConditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  "Evaluated",
+		Message: fmt.Sprintf("Scoring expression evaluated to %d", score),
+	})
+	return score
+}
+
+// expressionInput builds the variables available to a scoring expression
+func expressionInput(monitor *weebcastv1alpha1.AnimeMonitor, previous weebcastv1alpha1.AnimeActivityMetrics, since metav1.Time, now time.Time) scoring.Input {
+	current := expressionMetrics(monitor.Status.Metrics)
+	before := expressionMetrics(previous)
+
+	// Without a previous poll every delta is zero
+	delta := make(map[string]interface{}, len(current)+1)
+	for name, value := range current {
+		switch v := value.(type) {
+		case int64:
+			if since.IsZero() {
+				delta[name] = int64(0)
+			} else {
+				delta[name] = v - before[name].(int64)
+			}
+		case float64:
+			if since.IsZero() {
+				delta[name] = 0.0
+			} else {
+				delta[name] = v - before[name].(float64)
+			}
+		}
+	}
+	hours := 0.0
+	if !since.IsZero() {
+		hours = now.Sub(since.Time).Hours()
+	}
+	delta["hours"] = hours
+
+	history := make([]map[string]interface{}, 0, len(monitor.Status.History))
+	for _, sample := range monitor.Status.History {
+		history = append(history, map[string]interface{}{
+			"timestamp":     sample.Timestamp.Time,
+			"members":       int64(sample.Members),
+			"watching":      int64(sample.Watching),
+			"score":         sample.Score,
+			"rank":          int64(sample.Rank),
+			"activityScore": int64(sample.ActivityScore),
+			"level":         string(sample.Level),
+		})
+	}
+
+	return scoring.Input{
+		Metrics: current,
+		Delta:   delta,
+		History: history,
+	}
+}
+
+// expressionMetrics maps metrics to the names listed in scoring.MetricNames
+func expressionMetrics(metrics weebcastv1alpha1.AnimeActivityMetrics) map[string]interface{} {
+	return map[string]interface{}{
+		"activeUsers": int64(metrics.ActiveUsers),
+		"watching":    int64(metrics.WatchingCount),
+		"completed":   int64(metrics.CompletedCount),
+		"dropped":     int64(metrics.DroppedCount),
+		"planToWatch": int64(metrics.PlanToWatchCount),
+		"score":       metrics.Score,
+		"scoredBy":    int64(metrics.ScoredByCount),
+		"rank":        int64(metrics.Rank),
+		"popularity":  int64(metrics.Popularity),
+		"members":     int64(metrics.Members),
+		"favorites":   int64(metrics.Favorites),
+	}
+}
@@ -0,0 +1,86 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	weebcastv1alpha1 "github.com/weebcast/weebcast-operator/api/v1alpha1"
+	"github.com/weebcast/weebcast-operator/pkg/webhook"
+)
+
+// publishedActivityFinalizer holds a deleted monitor until its payload has
+// been removed from publishers that list every monitor
+const publishedActivityFinalizer = "weebcast.com/published-activity"
+
+// activityRemovers returns the publishers that must forget deleted monitors
+func (r *AnimeMonitorReconciler) activityRemovers() []webhook.ActivityRemover {
+	var removers []webhook.ActivityRemover
+	for _, publisher := range r.Publishers {
+		if remover, ok := publisher.(webhook.ActivityRemover); ok {
+			removers = append(removers, remover)
+		}
+	}
+	return removers
+}
+
+// ensureFinalizer adds the published activity finalizer when a publisher
+// needs to be told about the monitor's deletion
+func (r *AnimeMonitorReconciler) ensureFinalizer(ctx context.Context, monitor *weebcastv1alpha1.AnimeMonitor) error {
+	if len(r.activityRemovers()) == 0 || !controllerutil.AddFinalizer(monitor, publishedActivityFinalizer) {
+		return nil
+	}
+	if err := r.Update(ctx, monitor); err != nil {
+		return fmt.Errorf("adding finalizer: %w", err)
+	}
+	return nil
+}
+
+// finalizeMonitor removes a deleted monitor's payload from every publisher
+// that lists monitors, then releases the finalizer. The payload is kept
+// while another monitor still publishes under the same key.
+func (r *AnimeMonitorReconciler) finalizeMonitor(ctx context.Context, monitor *weebcastv1alpha1.AnimeMonitor) error {
+	if !controllerutil.ContainsFinalizer(monitor, publishedActivityFinalizer) {
+		return nil
+	}
+
+	key := webhook.ActivityKey(monitor)
+	shared, err := r.keyShared(ctx, monitor, key)
+	if err != nil {
+		return err
+	}
+	if !shared {
+		for _, remover := range r.activityRemovers() {
+			if err := remover.RemoveActivity(ctx, key); err != nil {
+				return fmt.Errorf("removing %s from %s: %w", key, remover.Name(), err)
+			}
+		}
+	}
+
+	controllerutil.RemoveFinalizer(monitor, publishedActivityFinalizer)
+	if err := r.Update(ctx, monitor); err != nil {
+		return fmt.Errorf("removing finalizer: %w", err)
+	}
+	return nil
+}
+
+// keyShared reports whether another monitor that is not being deleted
+// publishes under key
+func (r *AnimeMonitorReconciler) keyShared(ctx context.Context, monitor *weebcastv1alpha1.AnimeMonitor, key string) (bool, error) {
+	var monitors weebcastv1alpha1.AnimeMonitorList
+	if err := r.List(ctx, &monitors); err != nil {
+		return false, fmt.Errorf("listing monitors: %w", err)
+	}
+
+	for i := range monitors.Items {
+		other := &monitors.Items[i]
+		if other.UID == monitor.UID || !other.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if webhook.ActivityKey(other) == key {
+			return true, nil
+		}
+	}
+	return false, nil
+}
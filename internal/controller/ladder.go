@@ -0,0 +1,114 @@
+package controller
+
+import (
+	"fmt"
+	"math"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	weebcastv1alpha1 "github.com/weebcast/weebcast-operator/api/v1alpha1"
+)
+
+// levelLadderConditionType reports whether spec.levelLadder is in use
+const levelLadderConditionType = "LevelLadder"
+
+// validLadder returns the monitor's level ladder, or nil when none is set or
+// it is invalid. An invalid ladder is reported through the LevelLadder
+// condition and the built-in levels are used instead.
+func validLadder(monitor *weebcastv1alpha1.AnimeMonitor) []weebcastv1alpha1.LevelStep {
+	steps := monitor.Spec.LevelLadder
+	if len(steps) == 0 {
+		meta.RemoveStatusCondition(&monitor.Status.Conditions, levelLadderConditionType)
+		return nil
+	}
+
+	if err := weebcastv1alpha1.ValidateLevelLadder(steps); err != nil {
+		meta.SetStatusCondition(&monitor.Status.Conditions, metav1.Condition{
+			Type:    levelLadderConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  "InvalidLadder",
+			Message: fmt.Sprintf("%v; using the built-in levels", err),
+		})
+		return nil
+	}
+
+	meta.SetStatusCondition(&monitor.Status.Conditions, metav1.Condition{
+		Type:    levelLadderConditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  "Applied",
+		Message: fmt.Sprintf("Using a ladder of %d levels", len(steps)),
+	})
+	return steps
+}
+
+// ladderThresholds derives the built-in level thresholds from a ladder. Each
+// built-in level starts at the first step mapped to it or above it; a level
+// no step reaches gets an unreachable threshold. The first step covers every
+// score below it, so its threshold counts as zero.
+func ladderThresholds(steps []weebcastv1alpha1.LevelStep) weebcastv1alpha1.ActivityThresholds {
+	start := func(level weebcastv1alpha1.ActivityLevel) int {
+		for i, step := range steps {
+			if step.MapsTo.Rank() >= level.Rank() {
+				if i == 0 {
+					return 0
+				}
+				return step.Threshold
+			}
+		}
+		return math.MaxInt32
+	}
+
+	return weebcastv1alpha1.ActivityThresholds{
+		Medium:   start(weebcastv1alpha1.ActivityLevelMedium),
+		High:     start(weebcastv1alpha1.ActivityLevelHigh),
+		Critical: start(weebcastv1alpha1.ActivityLevelCritical),
+		Source:   weebcastv1alpha1.ThresholdSourceLadder,
+	}
+}
+
+// ladderStep returns the step for a score within the committed built-in
+// level, so hysteresis and dwell time on the built-in level also hold the
+// custom level. A score outside the level's steps gets its nearest step.
+func ladderStep(steps []weebcastv1alpha1.LevelStep, level weebcastv1alpha1.ActivityLevel, score int) weebcastv1alpha1.LevelStep {
+	var (
+		step  weebcastv1alpha1.LevelStep
+		found bool
+	)
+	for _, candidate := range steps {
+		if candidate.MapsTo != level {
+			continue
+		}
+		if !found || candidate.Threshold <= score {
+			step = candidate
+			found = true
+		}
+	}
+	if found {
+		return step
+	}
+
+	// The level has no steps, e.g. after the ladder changed; go by score alone
+	step = steps[0]
+	for _, candidate := range steps[1:] {
+		if candidate.Threshold <= score {
+			step = candidate
+		}
+	}
+	return step
+}
+
+// applyLadder records the monitor's custom level for the committed built-in
+// level, or clears it when no valid ladder is set
+func applyLadder(monitor *weebcastv1alpha1.AnimeMonitor, score int) {
+	steps := monitor.Spec.LevelLadder
+	if len(steps) == 0 || weebcastv1alpha1.ValidateLevelLadder(steps) != nil {
+		monitor.Status.CustomLevel = ""
+		monitor.Status.CustomLevelIcon = ""
+		return
+	}
+
+	step := ladderStep(steps, monitor.Status.ActivityLevel, score)
+	monitor.Status.CustomLevel = step.Name
+	monitor.Status.CustomLevelIcon = step.Icon
+}
@@ -0,0 +1,72 @@
+package controller
+
+import (
+	"math"
+	"testing"
+
+	weebcastv1alpha1 "github.com/weebcast/weebcast-operator/api/v1alpha1"
+)
+
+func TestLadderThresholds(t *testing.T) {
+	tests := []struct {
+		name  string
+		steps []weebcastv1alpha1.LevelStep
+		want  [3]int
+	}{
+		{
+			name: "one step per level",
+			steps: []weebcastv1alpha1.LevelStep{
+				{Name: "Calm", Threshold: 0, MapsTo: low},
+				{Name: "Gale", Threshold: 600, MapsTo: medium},
+				{Name: "Storm", Threshold: 1400, MapsTo: high},
+				{Name: "Typhoon", Threshold: 2400, MapsTo: critical},
+			},
+			want: [3]int{600, 1400, 2400},
+		},
+		{
+			name: "levels start at their first step",
+			steps: []weebcastv1alpha1.LevelStep{
+				{Name: "Calm", Threshold: 0, MapsTo: low},
+				{Name: "Breezy", Threshold: 300, MapsTo: low},
+				{Name: "Gale", Threshold: 600, MapsTo: medium},
+				{Name: "Squall", Threshold: 800, MapsTo: medium},
+				{Name: "Typhoon", Threshold: 1000, MapsTo: high},
+				{Name: "Apocalypse", Threshold: 5000, MapsTo: critical},
+			},
+			want: [3]int{600, 1000, 5000},
+		},
+		{
+			name: "skipped level starts with the next one",
+			steps: []weebcastv1alpha1.LevelStep{
+				{Name: "Calm", Threshold: 0, MapsTo: low},
+				{Name: "Typhoon", Threshold: 1000, MapsTo: high},
+			},
+			want: [3]int{1000, 1000, math.MaxInt32},
+		},
+		{
+			name: "first step covers lower scores",
+			steps: []weebcastv1alpha1.LevelStep{
+				{Name: "Gale", Threshold: 600, MapsTo: medium},
+				{Name: "Storm", Threshold: 1400, MapsTo: high},
+			},
+			want: [3]int{0, 1400, math.MaxInt32},
+		},
+		{
+			name:  "single low step",
+			steps: []weebcastv1alpha1.LevelStep{{Name: "Calm", Threshold: 100, MapsTo: low}},
+			want:  [3]int{math.MaxInt32, math.MaxInt32, math.MaxInt32},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ladderThresholds(tt.steps)
+			if [3]int{got.Medium, got.High, got.Critical} != tt.want {
+				t.Errorf("ladderThresholds() = %d/%d/%d, want %v", got.Medium, got.High, got.Critical, tt.want)
+			}
+			if got.Source != weebcastv1alpha1.ThresholdSourceLadder {
+				t.Errorf("source = %s, want Ladder", got.Source)
+			}
+		})
+	}
+}
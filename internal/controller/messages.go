@@ -0,0 +1,152 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	weebcastv1alpha1 "github.com/weebcast/weebcast-operator/api/v1alpha1"
+	"github.com/weebcast/weebcast-operator/pkg/mal"
+	"github.com/weebcast/weebcast-operator/pkg/messages"
+)
+
+// messageTemplatesConditionType reports whether custom message templates loaded
+const messageTemplatesConditionType = "MessageTemplates"
+
+// templateRefreshInterval is how long a loaded template ConfigMap is used
+// before it is read from the API server again
+const templateRefreshInterval = time.Minute
+
+// loadedTemplates is the outcome of reading and parsing one template ConfigMap
+type loadedTemplates struct {
+	resourceVersion string
+	loadedAt        time.Time
+	templates       *messages.Templates
+	reason          string
+	err             error
+}
+
+// setForecastMessages renders the WeebcastStatus message for the monitor's
+// current level in every locale. Custom templates that fail to load or render
+// fall back to the built-in messages and are reported through the
+// MessageTemplates condition.
+func (r *AnimeMonitorReconciler) setForecastMessages(ctx context.Context, monitor *weebcastv1alpha1.AnimeMonitor, title string, activityScore int, previous weebcastv1alpha1.AnimeActivityMetrics, since metav1.Time, now time.Time) {
+	data := messages.Data{
+		Title:           title,
+		Level:           string(monitor.Status.ActivityLevel),
+		CustomLevel:     monitor.Status.CustomLevel,
+		CustomLevelIcon: monitor.Status.CustomLevelIcon,
+		ActivityScore:   activityScore,
+		Metrics:         monitor.Status.Metrics,
+		Season:          mal.CurrentSeason(now),
+	}
+	if !since.IsZero() {
+		data.Delta = metricsDelta(monitor.Status.Metrics, previous)
+		data.Elapsed = now.Sub(since.Time)
+	}
+
+	localized, err := r.messageTemplates(ctx, monitor, now).RenderAll(data)
+	if err != nil {
+		meta.SetStatusCondition(&monitor.Status.Conditions, metav1.Condition{
+			Type:    messageTemplatesConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  "RenderFailed",
+			Message: fmt.Sprintf("%v; using the built-in messages", err),
+		})
+		localized, _ = messages.Builtin().RenderAll(data)
+	}
+
+	monitor.Status.WeebcastStatus = localized[messages.DefaultLocale]
+	monitor.Status.LocalizedStatus = localized
+}
+
+// messageTemplates returns the templates from the ConfigMap named in
+// spec.messageTemplatesConfigMap, or the operator-wide MessageTemplates
+// ConfigMap, or the built-in messages when neither is set or loads
+func (r *AnimeMonitorReconciler) messageTemplates(ctx context.Context, monitor *weebcastv1alpha1.AnimeMonitor, now time.Time) *messages.Templates {
+	key := r.MessageTemplates
+	if monitor.Spec.MessageTemplatesConfigMap != "" {
+		key = client.ObjectKey{Namespace: monitor.Namespace, Name: monitor.Spec.MessageTemplatesConfigMap}
+	}
+	if key.Name == "" {
+		meta.RemoveStatusCondition(&monitor.Status.Conditions, messageTemplatesConditionType)
+		return messages.Builtin()
+	}
+
+	loaded := r.loadTemplates(ctx, key, now)
+	if loaded.err != nil {
+		meta.SetStatusCondition(&monitor.Status.Conditions, metav1.Condition{
+			Type:    messageTemplatesConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  loaded.reason,
+			Message: fmt.Sprintf("%v; using the built-in messages", loaded.err),
+		})
+		return messages.Builtin()
+	}
+
+	meta.SetStatusCondition(&monitor.Status.Conditions, metav1.Condition{
+		Type:    messageTemplatesConditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  "Loaded",
+		Message: fmt.Sprintf("Using message templates from ConfigMap %s", key),
+	})
+	return loaded.templates
+}
+
+// loadTemplates reads and parses a template ConfigMap. The outcome, failures
+// included, is reused for templateRefreshInterval, so monitors sharing a
+// ConfigMap do not read it on every poll, and templates are only parsed again
+// when the ConfigMap's resource version changes.
+func (r *AnimeMonitorReconciler) loadTemplates(ctx context.Context, key client.ObjectKey, now time.Time) *loadedTemplates {
+	var previous *loadedTemplates
+	if cached, ok := r.templates.Load(key); ok {
+		previous = cached.(*loadedTemplates)
+		if now.Sub(previous.loadedAt) < templateRefreshInterval {
+			return previous
+		}
+	}
+
+	reader := r.APIReader
+	if reader == nil {
+		reader = r.Client
+	}
+	loaded := &loadedTemplates{loadedAt: now}
+	configMap := &corev1.ConfigMap{}
+	if err := reader.Get(ctx, key, configMap); err != nil {
+		loaded.reason, loaded.err = "LoadFailed", fmt.Errorf("reading ConfigMap %s: %w", key, err)
+	} else if previous != nil && previous.resourceVersion == configMap.ResourceVersion {
+		loaded.resourceVersion, loaded.templates = previous.resourceVersion, previous.templates
+		loaded.reason, loaded.err = previous.reason, previous.err
+	} else {
+		loaded.resourceVersion = configMap.ResourceVersion
+		loaded.templates, loaded.err = messages.Parse(configMap.Data)
+		if loaded.err != nil {
+			loaded.reason, loaded.err = "InvalidTemplate", fmt.Errorf("ConfigMap %s: %w", key, loaded.err)
+		}
+	}
+
+	r.templates.Store(key, loaded)
+	return loaded
+}
+
+// metricsDelta returns the change of each metric from previous to current
+func metricsDelta(current, previous weebcastv1alpha1.AnimeActivityMetrics) weebcastv1alpha1.AnimeActivityMetrics {
+	return weebcastv1alpha1.AnimeActivityMetrics{
+		ActiveUsers:      current.ActiveUsers - previous.ActiveUsers,
+		WatchingCount:    current.WatchingCount - previous.WatchingCount,
+		CompletedCount:   current.CompletedCount - previous.CompletedCount,
+		DroppedCount:     current.DroppedCount - previous.DroppedCount,
+		PlanToWatchCount: current.PlanToWatchCount - previous.PlanToWatchCount,
+		Score:            current.Score - previous.Score,
+		ScoredByCount:    current.ScoredByCount - previous.ScoredByCount,
+		Rank:             current.Rank - previous.Rank,
+		Popularity:       current.Popularity - previous.Popularity,
+		Members:          current.Members - previous.Members,
+		Favorites:        current.Favorites - previous.Favorites,
+	}
+}
@@ -0,0 +1,92 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	weebcastv1alpha1 "github.com/weebcast/weebcast-operator/api/v1alpha1"
+	"github.com/weebcast/weebcast-operator/pkg/messages"
+)
+
+// configMapReader serves one ConfigMap, or NotFound when it is nil, and
+// counts the reads
+type configMapReader struct {
+	configMap *corev1.ConfigMap
+	reads     int
+}
+
+func (c *configMapReader) Get(_ context.Context, key client.ObjectKey, obj client.Object, _ ...client.GetOption) error {
+	c.reads++
+	if c.configMap == nil {
+		return apierrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, key.Name)
+	}
+	c.configMap.DeepCopyInto(obj.(*corev1.ConfigMap))
+	return nil
+}
+
+func (c *configMapReader) List(context.Context, client.ObjectList, ...client.ListOption) error {
+	return nil
+}
+
+func TestMessageTemplates(t *testing.T) {
+	start := time.Date(2026, 10, 5, 0, 0, 0, 0, time.UTC)
+	reader := &configMapReader{}
+	r := &AnimeMonitorReconciler{APIReader: reader}
+	monitor := &weebcastv1alpha1.AnimeMonitor{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "frieren"},
+		Spec:       weebcastv1alpha1.AnimeMonitorSpec{MessageTemplatesConfigMap: "forecasts"},
+	}
+	configMap := func(version, high string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{ResourceVersion: version},
+			Data:       map[string]string{"High": high},
+		}
+	}
+
+	steps := []struct {
+		name      string
+		configMap *corev1.ConfigMap
+		after     time.Duration
+		wantReads int
+		// wantReason is the MessageTemplates condition reason
+		wantReason string
+		// wantHigh is the rendered High message, empty for the built-in one
+		wantHigh string
+	}{
+		{name: "missing ConfigMap", after: 0, wantReads: 1, wantReason: "LoadFailed"},
+		{name: "failure reused", configMap: configMap("1", "custom"), after: 30 * time.Second, wantReads: 1, wantReason: "LoadFailed"},
+		{name: "loaded after refresh", configMap: configMap("1", "custom"), after: time.Minute, wantReads: 2, wantReason: "Loaded", wantHigh: "custom"},
+		{name: "edit not seen before refresh", configMap: configMap("2", "{{.Nope}}"), after: 90 * time.Second, wantReads: 2, wantReason: "Loaded", wantHigh: "custom"},
+		{name: "invalid edit", configMap: configMap("2", "{{.Nope}}"), after: 2 * time.Minute, wantReads: 3, wantReason: "InvalidTemplate"},
+		{name: "fixed edit", configMap: configMap("3", "fixed"), after: 3 * time.Minute, wantReads: 4, wantReason: "Loaded", wantHigh: "fixed"},
+	}
+
+	builtinHigh, _ := messages.Builtin().Render(messages.Data{Level: "High"})
+	for _, step := range steps {
+		reader.configMap = step.configMap
+		templates := r.messageTemplates(context.Background(), monitor, start.Add(step.after))
+
+		if reader.reads != step.wantReads {
+			t.Errorf("%s: %d reads, want %d", step.name, reader.reads, step.wantReads)
+		}
+		condition := meta.FindStatusCondition(monitor.Status.Conditions, messageTemplatesConditionType)
+		if condition == nil || condition.Reason != step.wantReason {
+			t.Errorf("%s: condition = %+v, want reason %s", step.name, condition, step.wantReason)
+		}
+		want := step.wantHigh
+		if want == "" {
+			want = builtinHigh
+		}
+		if got, err := templates.Render(messages.Data{Level: "High"}); err != nil || got != want {
+			t.Errorf("%s: High message = %q, %v; want %q", step.name, got, err, want)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	weebcastv1alpha1 "github.com/weebcast/weebcast-operator/api/v1alpha1"
+	"github.com/weebcast/weebcast-operator/pkg/projection"
+)
+
+// outlookConditionType reports whether the activity outlook could be projected
+const outlookConditionType = "Outlook"
+
+// Outlook defaults and bounds
+const (
+	defaultOutlookHorizon = 24 * time.Hour
+	maxOutlookHorizon     = 7 * 24 * time.Hour
+	maxOutlookBuckets     = 168
+	week                  = 7 * 24 * time.Hour
+)
+
+// updateOutlook projects the activity score over spec.outlook.horizon into
+// status.outlook. It must run after the latest sample has been recorded.
+// Without enough history the outlook is cleared and the Outlook condition
+// reports InsufficientHistory.
+func (r *AnimeMonitorReconciler) updateOutlook(ctx context.Context, monitor *weebcastv1alpha1.AnimeMonitor, now time.Time) {
+	spec := monitor.Spec.Outlook
+	if spec == nil {
+		monitor.Status.Outlook = nil
+		meta.RemoveStatusCondition(&monitor.Status.Conditions, outlookConditionType)
+		return
+	}
+
+	horizon := durationOrDefault(spec.Horizon, defaultOutlookHorizon)
+	if horizon > maxOutlookHorizon {
+		horizon = maxOutlookHorizon
+	}
+	defaultBucket := time.Hour
+	if horizon > 48*time.Hour {
+		defaultBucket = 6 * time.Hour
+	}
+	bucket := durationOrDefault(spec.Bucket, defaultBucket)
+	buckets := int(math.Ceil(float64(horizon) / float64(bucket)))
+	if buckets > maxOutlookBuckets {
+		buckets = maxOutlookBuckets
+	}
+
+	// A weekly cycle needs buckets that divide the week, and two weeks of data
+	season, window := 0, week
+	if outlookSeasonal(monitor) && week%bucket == 0 {
+		season, window = int(week/bucket), 3*week
+	}
+
+	points := r.recordedPoints(ctx, monitor, now.Add(-window))
+	method, projections, err := projection.Project(points, projection.Options{
+		Step:    bucket,
+		Horizon: buckets,
+		Season:  season,
+	})
+	if err != nil {
+		monitor.Status.Outlook = nil
+		condition := metav1.Condition{
+			Type:    outlookConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  "ProjectionFailed",
+			Message: err.Error(),
+		}
+		if errors.Is(err, projection.ErrInsufficientData) {
+			condition.Status = metav1.ConditionUnknown
+			condition.Reason = "InsufficientHistory"
+			condition.Message = fmt.Sprintf("Need at least %d buckets of %s history, have %d samples", projection.MinSteps, bucket, len(points))
+		}
+		meta.SetStatusCondition(&monitor.Status.Conditions, condition)
+		return
+	}
+
+	thresholds := manualThresholds(monitor.Spec)
+	if monitor.Status.EffectiveThresholds != nil {
+		thresholds = *monitor.Status.EffectiveThresholds
+	}
+
+	outlook := &weebcastv1alpha1.ActivityOutlook{
+		Method:      string(method),
+		GeneratedAt: metav1.NewTime(now),
+		Buckets:     make([]weebcastv1alpha1.OutlookBucket, 0, len(projections)),
+	}
+	for _, p := range projections {
+		score := nonNegative(p.Value)
+		outlook.Buckets = append(outlook.Buckets, weebcastv1alpha1.OutlookBucket{
+			Start: metav1.NewTime(p.Time),
+			Score: score,
+			Lower: nonNegative(p.Lower),
+			Upper: nonNegative(p.Upper),
+			Level: r.determineActivityLevel(score, thresholds, "", 0),
+		})
+	}
+	monitor.Status.Outlook = outlook
+
+	meta.SetStatusCondition(&monitor.Status.Conditions, metav1.Condition{
+		Type:    outlookConditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  "Projected",
+		Message: fmt.Sprintf("Projected %d buckets of %s with %s", len(outlook.Buckets), bucket, method),
+	})
+}
+
+// outlookSeasonal reports whether the outlook should model a weekly cycle
+func outlookSeasonal(monitor *weebcastv1alpha1.AnimeMonitor) bool {
+	switch monitor.Spec.Outlook.Seasonality {
+	case weebcastv1alpha1.OutlookSeasonalityWeekly:
+		return true
+	case weebcastv1alpha1.OutlookSeasonalityNone:
+		return false
+	default:
+		return monitor.Status.Broadcast != nil
+	}
+}
+
+// nonNegative rounds a projected score, clamping it at zero
+func nonNegative(v float64) int {
+	return int(math.Round(math.Max(0, v)))
+}
@@ -0,0 +1,197 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	weebcastv1alpha1 "github.com/weebcast/weebcast-operator/api/v1alpha1"
+	"github.com/weebcast/weebcast-operator/pkg/history"
+	"github.com/weebcast/weebcast-operator/pkg/projection"
+)
+
+// Defaults for spec.autoThresholds fields left unset
+const (
+	defaultCalibrationWindow   = 7 * 24 * time.Hour
+	defaultCalibrationInterval = time.Hour
+	defaultMediumPercentile    = 50
+	defaultHighPercentile      = 80
+	defaultCriticalPercentile  = 95
+	defaultCalibrationSamples  = 12
+)
+
+// autoThresholdsConditionType reports whether Auto thresholds are calibrated
+const autoThresholdsConditionType = "AutoThresholds"
+
+// manualThresholds returns the thresholds configured in the spec
+func manualThresholds(spec weebcastv1alpha1.AnimeMonitorSpec) weebcastv1alpha1.ActivityThresholds {
+	highThreshold := spec.HighActivityThreshold
+	mediumThreshold := spec.MediumActivityThreshold
+
+	if highThreshold == 0 {
+		highThreshold = 1000
+	}
+	if mediumThreshold == 0 {
+		mediumThreshold = 500
+	}
+
+	return weebcastv1alpha1.ActivityThresholds{
+		Medium:   mediumThreshold,
+		High:     highThreshold,
+		Critical: highThreshold * 2,
+		Source:   weebcastv1alpha1.ThresholdSourceManual,
+	}
+}
+
+// effectiveThresholds returns the thresholds to use for this check. A level
+// ladder takes precedence over everything else. In Auto mode they are
+// recalibrated from the history store once per recalculate interval, falling
+// back to the manual thresholds until enough samples have been recorded, or
+// altogether without a history store, as status.history is too short to
+// cover the calibration window. Auto mode's state is reported through the
+// AutoThresholds condition.
+func (r *AnimeMonitorReconciler) effectiveThresholds(ctx context.Context, monitor *weebcastv1alpha1.AnimeMonitor, now time.Time) weebcastv1alpha1.ActivityThresholds {
+	if ladder := validLadder(monitor); ladder != nil {
+		meta.RemoveStatusCondition(&monitor.Status.Conditions, autoThresholdsConditionType)
+		return ladderThresholds(ladder)
+	}
+
+	manual := manualThresholds(monitor.Spec)
+	if monitor.Spec.ThresholdMode != weebcastv1alpha1.ThresholdModeAuto {
+		meta.RemoveStatusCondition(&monitor.Status.Conditions, autoThresholdsConditionType)
+		return manual
+	}
+
+	if r.HistoryStore == nil {
+		meta.SetStatusCondition(&monitor.Status.Conditions, metav1.Condition{
+			Type:    autoThresholdsConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  "HistoryStoreRequired",
+			Message: "Auto thresholds are calibrated from the history store, which is not configured (--history-db); using the manual thresholds",
+		})
+		return manual
+	}
+
+	auto := weebcastv1alpha1.AutoThresholdsSpec{}
+	if monitor.Spec.AutoThresholds != nil {
+		auto = *monitor.Spec.AutoThresholds
+	}
+	window := durationOrDefault(auto.Window, defaultCalibrationWindow)
+	interval := durationOrDefault(auto.RecalculateInterval, defaultCalibrationInterval)
+
+	// Reuse the last calibration until it is due
+	current := monitor.Status.EffectiveThresholds
+	if current != nil && current.Source == weebcastv1alpha1.ThresholdSourceAuto &&
+		current.CalculatedAt != nil && now.Sub(current.CalculatedAt.Time) < interval {
+		return *current
+	}
+
+	scores := r.recordedScores(ctx, monitor, now.Add(-window))
+	minSamples := intOrDefault(auto.MinSamples, defaultCalibrationSamples)
+	if len(scores) < minSamples {
+		meta.SetStatusCondition(&monitor.Status.Conditions, metav1.Condition{
+			Type:    autoThresholdsConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  "InsufficientSamples",
+			Message: fmt.Sprintf("%d of %d samples recorded in the last %s; using the manual thresholds", len(scores), minSamples, window),
+		})
+		manual.Samples = len(scores)
+		return manual
+	}
+
+	sort.Ints(scores)
+	thresholds := weebcastv1alpha1.ActivityThresholds{
+		Medium:       percentile(scores, intOrDefault(auto.MediumPercentile, defaultMediumPercentile)),
+		High:         percentile(scores, intOrDefault(auto.HighPercentile, defaultHighPercentile)),
+		Critical:     percentile(scores, intOrDefault(auto.CriticalPercentile, defaultCriticalPercentile)),
+		Source:       weebcastv1alpha1.ThresholdSourceAuto,
+		Samples:      len(scores),
+		CalculatedAt: &metav1.Time{Time: now},
+	}
+
+	// Keep the levels ordered even when scores are flat or percentiles overlap
+	if thresholds.High <= thresholds.Medium {
+		thresholds.High = thresholds.Medium + 1
+	}
+	if thresholds.Critical <= thresholds.High {
+		thresholds.Critical = thresholds.High + 1
+	}
+
+	meta.SetStatusCondition(&monitor.Status.Conditions, metav1.Condition{
+		Type:    autoThresholdsConditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  "Calibrated",
+		Message: fmt.Sprintf("Calibrated from %d samples recorded in the last %s", len(scores), window),
+	})
+	return thresholds
+}
+
+// recordedScores returns the monitor's activity scores recorded since the
+// given time, from the history store when readable or status.history otherwise
+func (r *AnimeMonitorReconciler) recordedScores(ctx context.Context, monitor *weebcastv1alpha1.AnimeMonitor, since time.Time) []int {
+	points := r.recordedPoints(ctx, monitor, since)
+	scores := make([]int, 0, len(points))
+	for _, point := range points {
+		scores = append(scores, int(point.Value))
+	}
+	return scores
+}
+
+// recordedPoints returns the monitor's activity scores recorded since the
+// given time with their timestamps, oldest first, from the history store when
+// configured or status.history otherwise
+func (r *AnimeMonitorReconciler) recordedPoints(ctx context.Context, monitor *weebcastv1alpha1.AnimeMonitor, since time.Time) []projection.Point {
+	var points []projection.Point
+
+	if r.HistoryStore != nil {
+		name := client.ObjectKeyFromObject(monitor).String()
+		err := r.HistoryStore.Range(name, since, time.Time{}, func(sample history.Sample) error {
+			points = append(points, projection.Point{Time: sample.Timestamp, Value: float64(sample.ActivityScore)})
+			return nil
+		})
+		if err == nil {
+			return points
+		}
+		log.FromContext(ctx).Error(err, "Failed to read history store, using status.history", "monitor", name)
+		points = nil
+	}
+
+	for _, sample := range monitor.Status.History {
+		if !sample.Timestamp.Time.Before(since) {
+			points = append(points, projection.Point{Time: sample.Timestamp.Time, Value: float64(sample.ActivityScore)})
+		}
+	}
+	return points
+}
+
+// percentile returns the nearest-rank percentile of sorted values
+func percentile(sorted []int, p int) int {
+	rank := int(math.Ceil(float64(p) / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// durationOrDefault returns d, or def when d is unset
+func durationOrDefault(d *metav1.Duration, def time.Duration) time.Duration {
+	if d == nil || d.Duration <= 0 {
+		return def
+	}
+	return d.Duration
+}
+
+// intOrDefault returns v, or def when v is unset
+func intOrDefault(v, def int) int {
+	if v <= 0 {
+		return def
+	}
+	return v
+}
@@ -0,0 +1,158 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	weebcastv1alpha1 "github.com/weebcast/weebcast-operator/api/v1alpha1"
+	"github.com/weebcast/weebcast-operator/pkg/history"
+)
+
+func TestPercentile(t *testing.T) {
+	deciles := []int{10, 20, 30, 40, 50, 60, 70, 80, 90, 100}
+
+	tests := []struct {
+		name   string
+		sorted []int
+		p      int
+		want   int
+	}{
+		{name: "median", sorted: deciles, p: 50, want: 50},
+		{name: "80th", sorted: deciles, p: 80, want: 80},
+		{name: "rounds the rank up", sorted: deciles, p: 95, want: 100},
+		{name: "just above a rank", sorted: deciles, p: 51, want: 60},
+		{name: "zero is the minimum", sorted: deciles, p: 0, want: 10},
+		{name: "100 is the maximum", sorted: deciles, p: 100, want: 100},
+		{name: "single value", sorted: []int{42}, p: 95, want: 42},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := percentile(tt.sorted, tt.p); got != tt.want {
+				t.Errorf("percentile(%d) = %d, want %d", tt.p, got, tt.want)
+			}
+		})
+	}
+}
+
+// scoreSamples returns one sample per score, every ten minutes up to at
+func scoreSamples(at time.Time, scores ...int) []history.Sample {
+	samples := make([]history.Sample, len(scores))
+	for i, score := range scores {
+		samples[i] = history.Sample{
+			Timestamp:     at.Add(-time.Duration(len(scores)-1-i) * 10 * time.Minute),
+			ActivityScore: score,
+		}
+	}
+	return samples
+}
+
+func TestEffectiveThresholdsAuto(t *testing.T) {
+	now := backtestStart.Add(48 * time.Hour)
+	manual := [3]int{500, 1000, 2000}
+
+	steps := []struct {
+		name    string
+		noStore bool
+		// samples are the scores in the history store at this step
+		samples []history.Sample
+		after   time.Duration
+		want    [3]int
+		source  weebcastv1alpha1.ThresholdSource
+		reason  string
+	}{
+		{
+			name:    "no history store",
+			noStore: true,
+			samples: scoreSamples(now, 100, 200, 300, 400),
+			want:    manual,
+			source:  weebcastv1alpha1.ThresholdSourceManual,
+			reason:  "HistoryStoreRequired",
+		},
+		{
+			name:    "too few samples",
+			samples: scoreSamples(now, 100, 200, 300),
+			want:    manual,
+			source:  weebcastv1alpha1.ThresholdSourceManual,
+			reason:  "InsufficientSamples",
+		},
+		{
+			name:    "samples outside the window do not count",
+			samples: append(scoreSamples(now.Add(-25*time.Hour), 900, 900), scoreSamples(now, 100, 200, 300)...),
+			want:    manual,
+			source:  weebcastv1alpha1.ThresholdSourceManual,
+			reason:  "InsufficientSamples",
+		},
+		{
+			name:    "calibrated",
+			samples: scoreSamples(now, 100, 200, 300, 400, 500, 600, 700, 800, 900, 1000),
+			want:    [3]int{500, 800, 1000},
+			source:  weebcastv1alpha1.ThresholdSourceAuto,
+			reason:  "Calibrated",
+		},
+		{
+			name:    "cached until the recalculate interval",
+			samples: scoreSamples(now.Add(30*time.Minute), 5000, 5000, 5000, 5000),
+			after:   30 * time.Minute,
+			want:    [3]int{500, 800, 1000},
+			source:  weebcastv1alpha1.ThresholdSourceAuto,
+			reason:  "Calibrated",
+		},
+		{
+			name:    "recalculated when due",
+			samples: scoreSamples(now.Add(time.Hour), 1000, 2000, 3000, 4000),
+			after:   time.Hour,
+			want:    [3]int{2000, 4000, 4001},
+			source:  weebcastv1alpha1.ThresholdSourceAuto,
+			reason:  "Calibrated",
+		},
+		{
+			name:    "flat scores stay ordered",
+			samples: scoreSamples(now.Add(2*time.Hour), 700, 700, 700, 700),
+			after:   2 * time.Hour,
+			want:    [3]int{700, 701, 702},
+			source:  weebcastv1alpha1.ThresholdSourceAuto,
+			reason:  "Calibrated",
+		},
+	}
+
+	monitor := &weebcastv1alpha1.AnimeMonitor{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "frieren"},
+		Spec: weebcastv1alpha1.AnimeMonitorSpec{
+			ThresholdMode: weebcastv1alpha1.ThresholdModeAuto,
+			AutoThresholds: &weebcastv1alpha1.AutoThresholdsSpec{
+				Window:     &metav1.Duration{Duration: 24 * time.Hour},
+				MinSamples: 4,
+			},
+		},
+	}
+	for _, step := range steps {
+		r := &AnimeMonitorReconciler{HistoryStore: &replayStore{samples: step.samples}}
+		if step.noStore {
+			r.HistoryStore = nil
+		}
+
+		got := r.effectiveThresholds(context.Background(), monitor, now.Add(step.after))
+		monitor.Status.EffectiveThresholds = &got
+
+		if [3]int{got.Medium, got.High, got.Critical} != step.want || got.Source != step.source {
+			t.Errorf("%s: thresholds = %d/%d/%d from %s, want %v from %s",
+				step.name, got.Medium, got.High, got.Critical, got.Source, step.want, step.source)
+		}
+		condition := meta.FindStatusCondition(monitor.Status.Conditions, autoThresholdsConditionType)
+		if condition == nil || condition.Reason != step.reason {
+			t.Errorf("%s: condition = %+v, want reason %s", step.name, condition, step.reason)
+		}
+	}
+
+	// Leaving Auto mode drops the condition
+	monitor.Spec.ThresholdMode = weebcastv1alpha1.ThresholdModeManual
+	(&AnimeMonitorReconciler{}).effectiveThresholds(context.Background(), monitor, now)
+	if condition := meta.FindStatusCondition(monitor.Status.Conditions, autoThresholdsConditionType); condition != nil {
+		t.Errorf("condition %+v kept in Manual mode", condition)
+	}
+}
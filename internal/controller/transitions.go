@@ -0,0 +1,73 @@
+package controller
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	weebcastv1alpha1 "github.com/weebcast/weebcast-operator/api/v1alpha1"
+)
+
+// withHysteresis lowers the thresholds of the current level and the levels
+// below it by percent, so leaving a level needs the score to fall clearly
+// below the threshold that was crossed to enter it
+func withHysteresis(thresholds weebcastv1alpha1.ActivityThresholds, current weebcastv1alpha1.ActivityLevel, percent int) weebcastv1alpha1.ActivityThresholds {
+	lower := func(threshold int) int {
+		return threshold - threshold*percent/100
+	}
+
+	rank := current.Rank()
+	if rank >= weebcastv1alpha1.ActivityLevelMedium.Rank() {
+		thresholds.Medium = lower(thresholds.Medium)
+	}
+	if rank >= weebcastv1alpha1.ActivityLevelHigh.Rank() {
+		thresholds.High = lower(thresholds.High)
+	}
+	if rank >= weebcastv1alpha1.ActivityLevelCritical.Rank() {
+		thresholds.Critical = lower(thresholds.Critical)
+	}
+	return thresholds
+}
+
+// transitionLevel returns the level the monitor should report for this
+// score, applying the hysteresis band and minimum dwell time from
+// spec.levelTransitions. A change held back by the dwell time is recorded in
+// status.pendingTransition until it either persists long enough or reverts.
+func (r *AnimeMonitorReconciler) transitionLevel(monitor *weebcastv1alpha1.AnimeMonitor, score int, thresholds weebcastv1alpha1.ActivityThresholds, now time.Time) weebcastv1alpha1.ActivityLevel {
+	current := monitor.Status.ActivityLevel
+	spec := monitor.Spec.LevelTransitions
+
+	// The first level, or no damping configured, is committed right away
+	if spec == nil || current == "" {
+		monitor.Status.PendingTransition = nil
+		return r.determineActivityLevel(score, thresholds, current, 0)
+	}
+
+	candidate := r.determineActivityLevel(score, thresholds, current, spec.HysteresisPercent)
+	if candidate == current {
+		monitor.Status.PendingTransition = nil
+		return current
+	}
+
+	minDwell := durationOrDefault(spec.MinDwell, 0)
+	if minDwell == 0 {
+		monitor.Status.PendingTransition = nil
+		return candidate
+	}
+
+	pending := monitor.Status.PendingTransition
+	if pending == nil || pending.Level != candidate {
+		monitor.Status.PendingTransition = &weebcastv1alpha1.PendingLevelTransition{
+			Level: candidate,
+			Since: metav1.NewTime(now),
+		}
+		return current
+	}
+
+	if now.Sub(pending.Since.Time) < minDwell {
+		return current
+	}
+
+	monitor.Status.PendingTransition = nil
+	return candidate
+}
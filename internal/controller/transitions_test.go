@@ -0,0 +1,142 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	weebcastv1alpha1 "github.com/weebcast/weebcast-operator/api/v1alpha1"
+)
+
+const (
+	low      = weebcastv1alpha1.ActivityLevelLow
+	medium   = weebcastv1alpha1.ActivityLevelMedium
+	high     = weebcastv1alpha1.ActivityLevelHigh
+	critical = weebcastv1alpha1.ActivityLevelCritical
+)
+
+// testThresholds are the manual defaults: Medium 500, High 1000, Critical 2000
+var testThresholds = weebcastv1alpha1.ActivityThresholds{Medium: 500, High: 1000, Critical: 2000}
+
+func TestWithHysteresis(t *testing.T) {
+	tests := []struct {
+		name       string
+		thresholds weebcastv1alpha1.ActivityThresholds
+		current    weebcastv1alpha1.ActivityLevel
+		percent    int
+		want       [3]int
+	}{
+		{name: "low keeps every threshold", thresholds: testThresholds, current: low, percent: 10, want: [3]int{500, 1000, 2000}},
+		{name: "medium lowers medium", thresholds: testThresholds, current: medium, percent: 10, want: [3]int{450, 1000, 2000}},
+		{name: "high lowers medium and high", thresholds: testThresholds, current: high, percent: 10, want: [3]int{450, 900, 2000}},
+		{name: "critical lowers all", thresholds: testThresholds, current: critical, percent: 10, want: [3]int{450, 900, 1800}},
+		{name: "zero percent", thresholds: testThresholds, current: critical, percent: 0, want: [3]int{500, 1000, 2000}},
+		{name: "first level", thresholds: testThresholds, current: "", percent: 10, want: [3]int{500, 1000, 2000}},
+		{
+			name:       "rounds the band down",
+			thresholds: weebcastv1alpha1.ActivityThresholds{Medium: 333, High: 999, Critical: 1998},
+			current:    critical,
+			percent:    10,
+			want:       [3]int{300, 900, 1799},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := withHysteresis(tt.thresholds, tt.current, tt.percent)
+			if [3]int{got.Medium, got.High, got.Critical} != tt.want {
+				t.Errorf("withHysteresis() = %d/%d/%d, want %v", got.Medium, got.High, got.Critical, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransitionLevelHysteresis(t *testing.T) {
+	band := &weebcastv1alpha1.LevelTransitionSpec{HysteresisPercent: 10}
+
+	tests := []struct {
+		name    string
+		spec    *weebcastv1alpha1.LevelTransitionSpec
+		current weebcastv1alpha1.ActivityLevel
+		score   int
+		want    weebcastv1alpha1.ActivityLevel
+	}{
+		{name: "high holds at the band edge", spec: band, current: high, score: 900, want: high},
+		{name: "high leaves below the band", spec: band, current: high, score: 899, want: medium},
+		{name: "rising needs the full threshold", spec: band, current: medium, score: 999, want: medium},
+		{name: "rising at the threshold", spec: band, current: medium, score: 1000, want: high},
+		{name: "medium holds at the band edge", spec: band, current: medium, score: 450, want: medium},
+		{name: "medium leaves below the band", spec: band, current: medium, score: 449, want: low},
+		{name: "critical holds at the band edge", spec: band, current: critical, score: 1800, want: critical},
+		{name: "critical leaves below the band", spec: band, current: critical, score: 1799, want: high},
+		{name: "critical falls through several levels", spec: band, current: critical, score: 100, want: low},
+		{name: "first level ignores the band", spec: band, current: "", score: 950, want: medium},
+		{name: "no damping configured", spec: nil, current: high, score: 950, want: medium},
+	}
+
+	r := &AnimeMonitorReconciler{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			monitor := &weebcastv1alpha1.AnimeMonitor{
+				Spec:   weebcastv1alpha1.AnimeMonitorSpec{LevelTransitions: tt.spec},
+				Status: weebcastv1alpha1.AnimeMonitorStatus{ActivityLevel: tt.current},
+			}
+			if got := r.transitionLevel(monitor, tt.score, testThresholds, backtestStart); got != tt.want {
+				t.Errorf("transitionLevel(%d) from %s = %s, want %s", tt.score, tt.current, got, tt.want)
+			}
+			if monitor.Status.PendingTransition != nil {
+				t.Errorf("pending transition %+v without a dwell time", monitor.Status.PendingTransition)
+			}
+		})
+	}
+}
+
+func TestTransitionLevelDwell(t *testing.T) {
+	monitor := &weebcastv1alpha1.AnimeMonitor{
+		Spec: weebcastv1alpha1.AnimeMonitorSpec{
+			LevelTransitions: &weebcastv1alpha1.LevelTransitionSpec{MinDwell: &metav1.Duration{Duration: time.Hour}},
+		},
+		Status: weebcastv1alpha1.AnimeMonitorStatus{ActivityLevel: low},
+	}
+
+	steps := []struct {
+		name  string
+		after time.Duration
+		score int
+		want  weebcastv1alpha1.ActivityLevel
+		// pending and pendingSince describe status.pendingTransition, empty when cleared
+		pending      weebcastv1alpha1.ActivityLevel
+		pendingSince time.Duration
+	}{
+		{name: "change held back", after: 0, score: 600, want: low, pending: medium, pendingSince: 0},
+		{name: "still within the dwell", after: 30 * time.Minute, score: 600, want: low, pending: medium, pendingSince: 0},
+		{name: "committed after the dwell", after: time.Hour, score: 600, want: medium},
+		{name: "next change held back", after: 2 * time.Hour, score: 1200, want: medium, pending: high, pendingSince: 2 * time.Hour},
+		{name: "reverting clears it", after: 150 * time.Minute, score: 500, want: medium},
+		{name: "dwell starts over", after: 3 * time.Hour, score: 1200, want: medium, pending: high, pendingSince: 3 * time.Hour},
+		{name: "new target resets the dwell", after: 210 * time.Minute, score: 2500, want: medium, pending: critical, pendingSince: 210 * time.Minute},
+		{name: "old dwell does not count", after: 4 * time.Hour, score: 2500, want: medium, pending: critical, pendingSince: 210 * time.Minute},
+		{name: "new target committed", after: 270 * time.Minute, score: 2500, want: critical},
+	}
+
+	r := &AnimeMonitorReconciler{}
+	for _, step := range steps {
+		got := r.transitionLevel(monitor, step.score, testThresholds, backtestStart.Add(step.after))
+		if got != step.want {
+			t.Errorf("%s: level = %s, want %s", step.name, got, step.want)
+		}
+		monitor.Status.ActivityLevel = got
+
+		pending := monitor.Status.PendingTransition
+		switch {
+		case step.pending == "" && pending != nil:
+			t.Errorf("%s: pending transition %+v, want none", step.name, pending)
+		case step.pending != "" && pending == nil:
+			t.Errorf("%s: no pending transition, want %s", step.name, step.pending)
+		case step.pending != "" && (pending.Level != step.pending || !pending.Since.Time.Equal(backtestStart.Add(step.pendingSince))):
+			t.Errorf("%s: pending transition to %s since %s, want %s since %s",
+				step.name, pending.Level, pending.Since.Time, step.pending, backtestStart.Add(step.pendingSince))
+		}
+	}
+}
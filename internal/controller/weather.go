@@ -0,0 +1,85 @@
+package controller
+
+import (
+	"math"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	weebcastv1alpha1 "github.com/weebcast/weebcast-operator/api/v1alpha1"
+)
+
+// weatherConditions maps activity levels onto weather
+var weatherConditions = map[weebcastv1alpha1.ActivityLevel]weebcastv1alpha1.WeatherCondition{
+	weebcastv1alpha1.ActivityLevelLow:      weebcastv1alpha1.WeatherConditionClear,
+	weebcastv1alpha1.ActivityLevelMedium:   weebcastv1alpha1.WeatherConditionCloudy,
+	weebcastv1alpha1.ActivityLevelHigh:     weebcastv1alpha1.WeatherConditionStorm,
+	weebcastv1alpha1.ActivityLevelCritical: weebcastv1alpha1.WeatherConditionTyphoon,
+}
+
+// weatherForecast describes the monitor's current level, metrics and
+// score trend as weather. It must run before the latest score is recorded in
+// status.history, whose last sample is taken as the previous score.
+func weatherForecast(monitor *weebcastv1alpha1.AnimeMonitor, activityScore int, previous weebcastv1alpha1.AnimeActivityMetrics, since metav1.Time) *weebcastv1alpha1.WeatherForecast {
+	level := monitor.Status.ActivityLevel
+	condition, ok := weatherConditions[level]
+	if !ok {
+		level, condition = weebcastv1alpha1.ActivityLevelLow, weebcastv1alpha1.WeatherConditionClear
+	}
+
+	forecast := &weebcastv1alpha1.WeatherForecast{
+		Condition: condition,
+		Icon:      level.Icon(),
+		// A score of 0 is -15°C, 7 a pleasant 20°C and a perfect 10 is 35°C
+		TemperatureCelsius: int(math.Round(monitor.Status.Metrics.Score*5 - 15)),
+	}
+
+	// A level ladder's icon replaces the built-in one, as in the badge and messages
+	if monitor.Status.CustomLevelIcon != "" {
+		forecast.Icon = monitor.Status.CustomLevelIcon
+	}
+
+	// Wind grows with the square root of member growth, so 100 new members
+	// an hour is a 20 km/h breeze and 10,000 a 200 km/h typhoon
+	if !since.IsZero() {
+		if hours := monitor.Status.LastChecked.Sub(since.Time).Hours(); hours > 0 {
+			growth := float64(monitor.Status.Metrics.Members-previous.Members) / hours
+			if growth > 0 {
+				forecast.WindSpeedKmh = int(math.Round(2 * math.Sqrt(growth)))
+			}
+		}
+	}
+
+	if monitor.Status.EffectiveThresholds != nil {
+		forecast.PrecipitationChance = precipitationChance(monitor, activityScore, level, *monitor.Status.EffectiveThresholds)
+	}
+	return forecast
+}
+
+// precipitationChance estimates the chance of reaching the next level by
+// extrapolating the score one poll ahead and measuring how far it gets from
+// the current level's threshold towards the next one. At Critical there is
+// no next level and it is already pouring.
+func precipitationChance(monitor *weebcastv1alpha1.AnimeMonitor, activityScore int, level weebcastv1alpha1.ActivityLevel, thresholds weebcastv1alpha1.ActivityThresholds) int {
+	var floor, next int
+	switch level {
+	case weebcastv1alpha1.ActivityLevelCritical:
+		return 100
+	case weebcastv1alpha1.ActivityLevelHigh:
+		floor, next = thresholds.High, thresholds.Critical
+	case weebcastv1alpha1.ActivityLevelMedium:
+		floor, next = thresholds.Medium, thresholds.High
+	default:
+		floor, next = 0, thresholds.Medium
+	}
+	if next <= floor {
+		return 100
+	}
+
+	projected := activityScore
+	if len(monitor.Status.History) > 0 {
+		projected += activityScore - lastActivityScore(monitor)
+	}
+
+	chance := float64(projected-floor) / float64(next-floor) * 100
+	return int(math.Round(math.Max(0, math.Min(100, chance))))
+}
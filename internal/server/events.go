@@ -0,0 +1,234 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/weebcast/weebcast-operator/pkg/webhook"
+)
+
+const (
+	// eventHistorySize is how many events are kept for Last-Event-ID resumption
+	eventHistorySize = 256
+
+	// subscriberBuffer is how many events a slow client may fall behind before it is dropped
+	subscriberBuffer = 32
+
+	// keepAliveInterval is how often an idle stream sends a comment to keep proxies from closing it
+	keepAliveInterval = 30 * time.Second
+)
+
+// ActivityEvent describes a change to a monitor's published state
+type ActivityEvent struct {
+	ID          uint64                   `json:"id"`
+	Key         string                   `json:"key"`
+	MonitorName string                   `json:"monitorName"`
+	Changes     []string                 `json:"changes"`
+	Payload     *webhook.ActivityPayload `json:"payload"`
+}
+
+// EventStream turns reconcile results into ActivityEvents and fans them out
+// to Server-Sent Events clients. It implements webhook.Publisher so the
+// reconciler feeds it alongside the other publishers.
+type EventStream struct {
+	mu          sync.Mutex
+	nextID      uint64
+	last        map[string]*webhook.ActivityPayload
+	history     []ActivityEvent
+	subscribers map[chan ActivityEvent]struct{}
+}
+
+// NewEventStream creates a new event stream
+func NewEventStream() *EventStream {
+	return &EventStream{
+		nextID:      1,
+		last:        make(map[string]*webhook.ActivityPayload),
+		subscribers: make(map[chan ActivityEvent]struct{}),
+	}
+}
+
+// Name identifies the event stream in logs
+func (e *EventStream) Name() string {
+	return "events"
+}
+
+// PushActivity emits an event when the activity level, Weebcast status or
+// trending list of a monitor changed since its previous reconcile
+func (e *EventStream) PushActivity(_ context.Context, key string, payload *webhook.ActivityPayload) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	changes := diffPayloads(e.last[key], payload)
+	e.last[key] = payload
+	if len(changes) == 0 {
+		return nil
+	}
+
+	event := ActivityEvent{
+		ID:          e.nextID,
+		Key:         key,
+		MonitorName: payload.MonitorName,
+		Changes:     changes,
+		Payload:     payload,
+	}
+	e.nextID++
+
+	e.history = append(e.history, event)
+	if len(e.history) > eventHistorySize {
+		e.history = e.history[len(e.history)-eventHistorySize:]
+	}
+
+	for ch := range e.subscribers {
+		select {
+		case ch <- event:
+		default:
+			// The client can't keep up; drop it and let it resume with Last-Event-ID
+			delete(e.subscribers, ch)
+			close(ch)
+		}
+	}
+
+	return nil
+}
+
+// diffPayloads returns the names of the fields that changed
+func diffPayloads(previous, current *webhook.ActivityPayload) []string {
+	if previous == nil {
+		return []string{"activityLevel", "weebcastStatus", "trendingAnime"}
+	}
+
+	var changes []string
+	if previous.ActivityLevel != current.ActivityLevel {
+		changes = append(changes, "activityLevel")
+	}
+	if previous.WeebcastStatus != current.WeebcastStatus {
+		changes = append(changes, "weebcastStatus")
+	}
+	if !sameTrending(previous.TrendingAnime, current.TrendingAnime) {
+		changes = append(changes, "trendingAnime")
+	}
+	return changes
+}
+
+// sameTrending compares trending lists by anime ID and order
+func sameTrending(a, b []webhook.TrendingItem) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].ID != b[i].ID {
+			return false
+		}
+	}
+	return true
+}
+
+// subscribe registers a client and returns the buffered events after lastID
+func (e *EventStream) subscribe(lastID uint64) (chan ActivityEvent, []ActivityEvent) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var backlog []ActivityEvent
+	for _, event := range e.history {
+		if event.ID > lastID {
+			backlog = append(backlog, event)
+		}
+	}
+
+	ch := make(chan ActivityEvent, subscriberBuffer)
+	e.subscribers[ch] = struct{}{}
+	return ch, backlog
+}
+
+// unsubscribe removes a client if it is still registered
+func (e *EventStream) unsubscribe(ch chan ActivityEvent) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if _, ok := e.subscribers[ch]; ok {
+		delete(e.subscribers, ch)
+		close(ch)
+	}
+}
+
+// ServeHTTP streams events as Server-Sent Events. Clients may filter with
+// one or more ?monitor=<name> or ?key=<activity key> parameters and resume
+// with the Last-Event-ID header (or ?lastEventId=).
+func (e *EventStream) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	filter := make(map[string]bool)
+	for _, name := range r.URL.Query()["monitor"] {
+		filter[name] = true
+	}
+	for _, key := range r.URL.Query()["key"] {
+		filter[key] = true
+	}
+
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = r.URL.Query().Get("lastEventId")
+	}
+	lastID, _ := strconv.ParseUint(lastEventID, 10, 64)
+
+	ch, backlog := e.subscribe(lastID)
+	defer e.unsubscribe(ch)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	send := func(event ActivityEvent) error {
+		if len(filter) > 0 && !filter[event.MonitorName] && !filter[event.Key] {
+			return nil
+		}
+		data, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "id: %d\nevent: activity\ndata: %s\n\n", event.ID, data); err != nil {
+			return err
+		}
+		flusher.Flush()
+		return nil
+	}
+
+	for _, event := range backlog {
+		if err := send(event); err != nil {
+			return
+		}
+	}
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-ch:
+			if !ok {
+				return
+			}
+			if err := send(event); err != nil {
+				return
+			}
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
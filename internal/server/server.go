@@ -0,0 +1,191 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	weebcastv1alpha1 "github.com/weebcast/weebcast-operator/api/v1alpha1"
+	"github.com/weebcast/weebcast-operator/pkg/webhook"
+)
+
+// endpoints is returned with 404 responses, mirroring the worker
+var endpoints = []string{
+	"/api/activity - Overall MAL activity",
+	"/api/activity/all - All monitors",
+	"/api/anime/:id - Specific anime activity",
+	"/api/trending - Trending anime list",
+	"/api/seasonal - Current season anime",
+}
+
+// Server serves a read-only HTTP API compatible with the Cloudflare worker's
+// routes, built directly from the manager's informer cache of AnimeMonitors.
+// Every replica serves the worker routes; routes fed by the reconciler, which
+// only runs on the leader, are answered by the leader alone.
+type Server struct {
+	client  client.Reader
+	addr    string
+	mux     *http.ServeMux
+	elected <-chan struct{}
+}
+
+// New creates a new API server listening on addr
+func New(reader client.Reader, addr string) *Server {
+	s := &Server{
+		client: reader,
+		addr:   addr,
+		mux:    http.NewServeMux(),
+	}
+	s.mux.HandleFunc("/", s.handleAPI)
+	return s
+}
+
+// Handle registers an additional handler served by every replica
+func (s *Server) Handle(pattern string, handler http.Handler) *Server {
+	s.mux.Handle(pattern, handler)
+	return s
+}
+
+// HandleLeader registers a handler fed by the reconciler, such as the event
+// stream or feeds. Until this replica is elected leader it answers 503, as
+// its handlers never receive a reconcile.
+func (s *Server) HandleLeader(pattern string, handler http.Handler) *Server {
+	s.mux.Handle(pattern, s.leaderOnly(handler))
+	return s
+}
+
+// WithElected sets the channel closed once this replica is elected leader,
+// as returned by the manager's Elected. Without it every replica serves the
+// leader's routes.
+func (s *Server) WithElected(elected <-chan struct{}) *Server {
+	s.elected = elected
+	return s
+}
+
+// NeedLeaderElection allows every replica to serve the worker routes from
+// its own cache
+func (s *Server) NeedLeaderElection() bool {
+	return false
+}
+
+// leaderOnly answers 503 until this replica is elected leader
+func (s *Server) leaderOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.elected != nil {
+			select {
+			case <-s.elected:
+			default:
+				w.Header().Set("Retry-After", "10")
+				writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "Served by the leader replica only"})
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Start serves the API until the context is cancelled. It implements manager.Runnable.
+func (s *Server) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("api-server")
+
+	srv := &http.Server{
+		Addr:              s.addr,
+		Handler:           withCORS(s.mux),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			logger.Error(err, "Failed to shut down API server")
+		}
+	}()
+
+	logger.Info("Serving weebcast API", "addr", s.addr)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// handleAPI serves the worker routes from the current monitor state
+func (s *Server) handleAPI(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "This API is read-only"})
+		return
+	}
+
+	payloads, err := s.Payloads(r.Context())
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	route := strings.TrimSuffix(r.URL.Path, "/")
+	docs := webhook.SiteDocuments(payloads, time.Now())
+
+	if doc, ok := docs[route]; ok {
+		writeJSON(w, http.StatusOK, doc)
+		return
+	}
+
+	if strings.HasPrefix(route, "/api/anime/") {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "Anime not being monitored"})
+		return
+	}
+
+	writeJSON(w, http.StatusNotFound, map[string]interface{}{
+		"error":     "Not found",
+		"endpoints": endpoints,
+	})
+}
+
+// Payloads returns the published payload of every AnimeMonitor, keyed by activity key
+func (s *Server) Payloads(ctx context.Context) (map[string]*webhook.ActivityPayload, error) {
+	monitors := &weebcastv1alpha1.AnimeMonitorList{}
+	if err := s.client.List(ctx, monitors); err != nil {
+		return nil, err
+	}
+
+	payloads := make(map[string]*webhook.ActivityPayload, len(monitors.Items))
+	for i := range monitors.Items {
+		monitor := &monitors.Items[i]
+		// Skip monitors that have not completed their first poll yet
+		if monitor.Status.ActivityLevel == "" {
+			continue
+		}
+		payloads[webhook.ActivityKey(monitor)] = webhook.NewActivityPayload(monitor)
+	}
+	return payloads, nil
+}
+
+// withCORS adds the same CORS headers as the worker and answers preflight requests
+func withCORS(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+
+		if r.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// writeJSON writes a JSON response with the given status code
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,35 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandleLeader(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	elected := make(chan struct{})
+	s := New(nil, ":0").WithElected(elected).
+		Handle("/api/everyone", ok).
+		HandleLeader("/api/leader", ok)
+
+	get := func(path string) int {
+		rec := httptest.NewRecorder()
+		s.mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec.Code
+	}
+
+	if code := get("/api/leader"); code != http.StatusServiceUnavailable {
+		t.Errorf("leader route before election = %d, want 503", code)
+	}
+	if code := get("/api/everyone"); code != http.StatusOK {
+		t.Errorf("shared route before election = %d, want 200", code)
+	}
+
+	close(elected)
+	if code := get("/api/leader"); code != http.StatusOK {
+		t.Errorf("leader route after election = %d, want 200", code)
+	}
+}
@@ -0,0 +1,203 @@
+package badge
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"html"
+	"net/http"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	weebcastv1alpha1 "github.com/weebcast/weebcast-operator/api/v1alpha1"
+	"github.com/weebcast/weebcast-operator/pkg/webhook"
+)
+
+const (
+	// svgContentType is the media type of rendered badges
+	svgContentType = "image/svg+xml"
+
+	// badgeLabel is the text on the left side of every badge
+	badgeLabel = "weeb weather"
+)
+
+// levelColor returns the badge color for an activity level
+func levelColor(level string) string {
+	switch level {
+	case "Critical":
+		return "#8e44ad"
+	case "High":
+		return "#e05d44"
+	case "Medium":
+		return "#dfb317"
+	case "Low":
+		return "#4c1"
+	default:
+		return "#9f9f9f"
+	}
+}
+
+// rendered is a cached badge
+type rendered struct {
+	level   string
+	message string
+	svg     []byte
+	etag    string
+}
+
+// Badges renders a small SVG status badge per monitor showing the weather
+// icon, activity level and MAL score. Monitors with a custom level ladder
+// show their custom level and icon instead. Badges are cached and only
+// regenerated when a monitor's level or displayed score changes. It implements
+// webhook.ActivityRemover so it is fed by the reconciler and forgets deleted
+// monitors.
+type Badges struct {
+	documents []webhook.DocumentPublisher
+
+	mu     sync.RWMutex
+	badges map[string]*rendered
+}
+
+// NewBadges creates a new badge renderer
+func NewBadges() *Badges {
+	return &Badges{
+		badges: make(map[string]*rendered),
+	}
+}
+
+// WithDocumentPublishers writes badges to the given publishers whenever
+// they are regenerated
+func (b *Badges) WithDocumentPublishers(publishers ...webhook.DocumentPublisher) *Badges {
+	b.documents = append(b.documents, publishers...)
+	return b
+}
+
+// Name identifies the badge renderer in logs
+func (b *Badges) Name() string {
+	return "badges"
+}
+
+// PushActivity regenerates the monitor's badge if its level or displayed
+// score changed
+func (b *Badges) PushActivity(ctx context.Context, key string, payload *webhook.ActivityPayload) error {
+	level := payload.ActivityLevel
+	if level == "" {
+		level = "Unknown"
+	}
+	message := fmt.Sprintf("%s %s", weebcastv1alpha1.ActivityLevel(level).Icon(), level)
+	if payload.Level != nil {
+		message = strings.TrimSpace(payload.Level.Icon + " " + payload.Level.Name)
+	}
+	if score := payload.Metrics.Score; score > 0 {
+		message = fmt.Sprintf("%s · %.2f", message, score)
+	}
+
+	b.mu.Lock()
+	if existing, ok := b.badges[key]; ok && existing.level == level && existing.message == message {
+		b.mu.Unlock()
+		return nil
+	}
+
+	svg := render(level, message)
+	sum := sha256.Sum256(svg)
+	b.badges[key] = &rendered{
+		level:   level,
+		message: message,
+		svg:     svg,
+		etag:    `"` + hex.EncodeToString(sum[:8]) + `"`,
+	}
+	b.mu.Unlock()
+
+	for _, publisher := range b.documents {
+		if err := publisher.PutDocument(ctx, "badges/"+key+".svg", svgContentType, svg); err != nil {
+			return fmt.Errorf("writing %s badge to %s: %w", key, publisher.Name(), err)
+		}
+	}
+	return nil
+}
+
+// RemoveActivity drops a deleted monitor's badge and deletes it from the
+// document publishers that support it
+func (b *Badges) RemoveActivity(ctx context.Context, key string) error {
+	b.mu.Lock()
+	delete(b.badges, key)
+	b.mu.Unlock()
+
+	for _, publisher := range b.documents {
+		remover, ok := publisher.(webhook.DocumentRemover)
+		if !ok {
+			continue
+		}
+		if err := remover.DeleteDocument(ctx, "badges/"+key+".svg"); err != nil {
+			return fmt.Errorf("deleting %s badge from %s: %w", key, publisher.Name(), err)
+		}
+	}
+	return nil
+}
+
+// ServeHTTP serves badges at /api/badge/<key>.svg
+func (b *Badges) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	key := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/badge/"), ".svg")
+
+	b.mu.RLock()
+	badge, ok := b.badges[key]
+	b.mu.RUnlock()
+	if !ok {
+		http.Error(w, "monitor not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", svgContentType)
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	w.Header().Set("ETag", badge.etag)
+
+	if r.Header.Get("If-None-Match") == badge.etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	_, _ = w.Write(badge.svg)
+}
+
+// render draws a badge with the given message, colored by activity level
+func render(level, message string) []byte {
+	labelWidth := textWidth(badgeLabel) + 10
+	messageWidth := textWidth(message) + 10
+	width := labelWidth + messageWidth
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="20" role="img" aria-label="%s: %s">`,
+		width, badgeLabel, html.EscapeString(message))
+	fmt.Fprintf(&sb, `<title>%s: %s</title>`, badgeLabel, html.EscapeString(message))
+	sb.WriteString(`<linearGradient id="s" x2="0" y2="100%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>`)
+	fmt.Fprintf(&sb, `<clipPath id="r"><rect width="%d" height="20" rx="3" fill="#fff"/></clipPath>`, width)
+	sb.WriteString(`<g clip-path="url(#r)">`)
+	fmt.Fprintf(&sb, `<rect width="%d" height="20" fill="#555"/>`, labelWidth)
+	fmt.Fprintf(&sb, `<rect x="%d" width="%d" height="20" fill="%s"/>`, labelWidth, messageWidth, levelColor(level))
+	fmt.Fprintf(&sb, `<rect width="%d" height="20" fill="url(#s)"/>`, width)
+	sb.WriteString(`</g>`)
+	sb.WriteString(`<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">`)
+	fmt.Fprintf(&sb, `<text x="%d" y="14">%s</text>`, labelWidth/2, badgeLabel)
+	fmt.Fprintf(&sb, `<text x="%d" y="14">%s</text>`, labelWidth+messageWidth/2, html.EscapeString(message))
+	sb.WriteString(`</g></svg>`)
+
+	return []byte(sb.String())
+}
+
+// textWidth estimates the rendered width of badge text in pixels
+func textWidth(text string) int {
+	width := 0
+	for _, r := range text {
+		switch {
+		case r == '\uFE0F':
+			// Variation selectors have no width
+		case utf8.RuneLen(r) > 2:
+			width += 14
+		default:
+			width += 7
+		}
+	}
+	return width
+}
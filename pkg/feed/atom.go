@@ -0,0 +1,432 @@
+package feed
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/weebcast/weebcast-operator/pkg/webhook"
+)
+
+const (
+	// maxEntriesPerMonitor bounds the transitions kept for each monitor
+	maxEntriesPerMonitor = 50
+
+	// atomContentType is the media type of Atom documents
+	atomContentType = "application/atom+xml; charset=utf-8"
+)
+
+// Transition is a single activity level change of a monitor
+type Transition struct {
+	Key           string
+	Title         string
+	PreviousLevel string
+	Level         string
+	Status        string
+	ChangedAt     time.Time
+}
+
+// AtomFeed records activity level transitions from the reconciler and
+// renders them as Atom feeds: one per monitor and a combined feed. It
+// implements webhook.ActivityRemover so it is fed alongside the other
+// publishers, and can write the rendered feeds to document publishers as
+// static files.
+// Feeds already written by a publisher that can read them back are reloaded
+// on first use, so published history carries on across restarts.
+type AtomFeed struct {
+	baseURL   string
+	documents []webhook.DocumentPublisher
+
+	mu          sync.Mutex
+	levels      map[string]string
+	transitions map[string][]Transition
+
+	// combinedLoaded and loaded record which published feeds were read back
+	combinedLoaded bool
+	loaded         map[string]bool
+}
+
+// NewAtomFeed creates a new feed. baseURL is used for feed IDs and links,
+// e.g. https://weebcast.com.
+func NewAtomFeed(baseURL string) *AtomFeed {
+	return &AtomFeed{
+		baseURL:     strings.TrimSuffix(baseURL, "/"),
+		levels:      make(map[string]string),
+		transitions: make(map[string][]Transition),
+		loaded:      make(map[string]bool),
+	}
+}
+
+// WithDocumentPublishers writes the feeds to the given publishers whenever
+// a transition is recorded
+func (f *AtomFeed) WithDocumentPublishers(publishers ...webhook.DocumentPublisher) *AtomFeed {
+	f.documents = append(f.documents, publishers...)
+	return f
+}
+
+// Name identifies the feed in logs
+func (f *AtomFeed) Name() string {
+	return "atom-feed"
+}
+
+// PushActivity records a transition when the monitor's activity level
+// changed. The published feed is read back first, so a level change while
+// the operator was down is recorded against the last published level. The
+// first payload of a monitor without a published feed seeds its feed with
+// the current level, dated by its last activity change; the documents are
+// only written once a transition has been seen.
+func (f *AtomFeed) PushActivity(ctx context.Context, key string, payload *webhook.ActivityPayload) error {
+	f.mu.Lock()
+	if err := f.loadLocked(ctx, key); err != nil {
+		f.mu.Unlock()
+		return err
+	}
+	previous, seen := f.levels[key]
+	if seen && previous == payload.ActivityLevel {
+		f.mu.Unlock()
+		return nil
+	}
+	f.levels[key] = payload.ActivityLevel
+
+	changedAt := payload.LastUpdated
+	if payload.LastActivityChange != nil {
+		changedAt = *payload.LastActivityChange
+	}
+
+	title := payload.AnimeName
+	if title == "" {
+		title = payload.MonitorName
+	}
+
+	transitions := append(f.transitions[key], Transition{
+		Key:           key,
+		Title:         title,
+		PreviousLevel: previous,
+		Level:         payload.ActivityLevel,
+		Status:        payload.WeebcastStatus,
+		ChangedAt:     changedAt,
+	})
+	if len(transitions) > maxEntriesPerMonitor {
+		transitions = transitions[len(transitions)-maxEntriesPerMonitor:]
+	}
+	f.transitions[key] = transitions
+	f.mu.Unlock()
+
+	if !seen {
+		return nil
+	}
+	return f.publishDocuments(ctx, key)
+}
+
+// RemoveActivity drops a deleted monitor's transitions, rewrites the
+// combined feed without them and deletes the monitor's feed from the
+// document publishers that support it
+func (f *AtomFeed) RemoveActivity(ctx context.Context, key string) error {
+	f.mu.Lock()
+	if err := f.loadLocked(ctx, key); err != nil {
+		f.mu.Unlock()
+		return err
+	}
+	_, seen := f.transitions[key]
+	delete(f.transitions, key)
+	delete(f.levels, key)
+	f.mu.Unlock()
+
+	if !seen || len(f.documents) == 0 {
+		return nil
+	}
+
+	combined, err := f.Render("")
+	if err != nil {
+		return err
+	}
+
+	for _, publisher := range f.documents {
+		if err := publisher.PutDocument(ctx, "feeds/activity.atom", atomContentType, combined); err != nil {
+			return fmt.Errorf("writing combined feed to %s: %w", publisher.Name(), err)
+		}
+		remover, ok := publisher.(webhook.DocumentRemover)
+		if !ok {
+			continue
+		}
+		if err := remover.DeleteDocument(ctx, "feeds/"+key+".atom"); err != nil {
+			return fmt.Errorf("deleting %s feed from %s: %w", key, publisher.Name(), err)
+		}
+	}
+
+	return nil
+}
+
+// loadLocked seeds the transitions from the published combined feed the
+// first time it is called, and those of a monitor from its own feed, which
+// keeps more entries, the first time the monitor is pushed
+func (f *AtomFeed) loadLocked(ctx context.Context, key string) error {
+	if !f.combinedLoaded {
+		transitions, err := f.readFeed(ctx, "feeds/activity.atom")
+		if err != nil {
+			return err
+		}
+		for _, t := range transitions {
+			if !f.loaded[t.Key] {
+				f.transitions[t.Key] = append(f.transitions[t.Key], t)
+			}
+		}
+		f.combinedLoaded = true
+	}
+
+	if f.loaded[key] {
+		return nil
+	}
+	transitions, err := f.readFeed(ctx, "feeds/"+key+".atom")
+	if err != nil {
+		return err
+	}
+	if len(transitions) > 0 {
+		f.transitions[key] = transitions
+	}
+	if t := f.transitions[key]; len(t) > 0 {
+		if _, ok := f.levels[key]; !ok {
+			f.levels[key] = t[len(t)-1].Level
+		}
+	}
+	f.loaded[key] = true
+	return nil
+}
+
+// readFeed returns the entries of a published feed, oldest first, from the
+// first document publisher that can read it back
+func (f *AtomFeed) readFeed(ctx context.Context, path string) ([]Transition, error) {
+	for _, publisher := range f.documents {
+		reader, ok := publisher.(webhook.DocumentReader)
+		if !ok {
+			continue
+		}
+
+		data, err := reader.GetDocument(ctx, path)
+		if errors.Is(err, webhook.ErrDocumentNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading %s from %s: %w", path, publisher.Name(), err)
+		}
+		return parseTransitions(data)
+	}
+	return nil, nil
+}
+
+// parseTransitions recovers the transitions from a feed written by Render,
+// oldest first. Entries not written by Render are skipped.
+func parseTransitions(data []byte) ([]Transition, error) {
+	var doc atomFeed
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("decoding feed: %w", err)
+	}
+
+	var transitions []Transition
+	for _, entry := range doc.Entries {
+		// IDs are <base URL>/feeds/<key>/<unix time>
+		_, id, ok := cutLast(entry.ID, "/feeds/")
+		if !ok {
+			continue
+		}
+		key, _, ok := cutLast(id, "/")
+		if !ok {
+			continue
+		}
+		// Titles are "<title>: <level>" or "<title>: <previous> → <level>"
+		title, levels, ok := cutLast(entry.Title, ": ")
+		if !ok {
+			continue
+		}
+		changedAt, err := time.Parse(time.RFC3339, entry.Updated)
+		if err != nil {
+			continue
+		}
+		previous, level, changed := strings.Cut(levels, " → ")
+		if !changed {
+			previous, level = "", levels
+		}
+
+		transitions = append(transitions, Transition{
+			Key:           key,
+			Title:         title,
+			PreviousLevel: previous,
+			Level:         level,
+			Status:        entry.Content.Body,
+			ChangedAt:     changedAt,
+		})
+	}
+
+	sort.SliceStable(transitions, func(i, j int) bool {
+		return transitions[i].ChangedAt.Before(transitions[j].ChangedAt)
+	})
+	return transitions, nil
+}
+
+// cutLast slices s around the last instance of sep
+func cutLast(s, sep string) (before, after string, found bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
+
+// publishDocuments writes the combined feed and the monitor's feed to every
+// document publisher
+func (f *AtomFeed) publishDocuments(ctx context.Context, key string) error {
+	if len(f.documents) == 0 {
+		return nil
+	}
+
+	combined, err := f.Render("")
+	if err != nil {
+		return err
+	}
+	monitor, err := f.Render(key)
+	if err != nil {
+		return err
+	}
+
+	for _, publisher := range f.documents {
+		if err := publisher.PutDocument(ctx, "feeds/activity.atom", atomContentType, combined); err != nil {
+			return fmt.Errorf("writing combined feed to %s: %w", publisher.Name(), err)
+		}
+		if err := publisher.PutDocument(ctx, "feeds/"+key+".atom", atomContentType, monitor); err != nil {
+			return fmt.Errorf("writing %s feed to %s: %w", key, publisher.Name(), err)
+		}
+	}
+
+	return nil
+}
+
+// Render returns the Atom document for a monitor key, or the combined feed
+// of all monitors when key is empty
+func (f *AtomFeed) Render(key string) ([]byte, error) {
+	f.mu.Lock()
+	var transitions []Transition
+	if key == "" {
+		for _, t := range f.transitions {
+			transitions = append(transitions, t...)
+		}
+	} else {
+		transitions = append(transitions, f.transitions[key]...)
+	}
+	f.mu.Unlock()
+
+	// Newest first, bounded like a single monitor's feed
+	sort.SliceStable(transitions, func(i, j int) bool {
+		return transitions[i].ChangedAt.After(transitions[j].ChangedAt)
+	})
+	if len(transitions) > maxEntriesPerMonitor {
+		transitions = transitions[:maxEntriesPerMonitor]
+	}
+
+	path := "/feeds/activity.atom"
+	title := "Weebcast - Weeb Weather Changes"
+	if key != "" {
+		path = "/feeds/" + key + ".atom"
+		title = "Weebcast - " + key
+		if len(transitions) > 0 {
+			title = "Weebcast - " + transitions[0].Title
+		}
+	}
+
+	doc := atomFeed{
+		Xmlns:  "http://www.w3.org/2005/Atom",
+		ID:     f.baseURL + path,
+		Title:  title,
+		Author: atomAuthor{Name: "Weebcast Operator"},
+		Links: []atomLink{
+			{Rel: "self", Href: f.baseURL + path},
+			{Rel: "alternate", Href: f.baseURL + "/"},
+		},
+	}
+	if len(transitions) > 0 {
+		doc.Updated = transitions[0].ChangedAt.UTC().Format(time.RFC3339)
+	} else {
+		doc.Updated = time.Now().UTC().Format(time.RFC3339)
+	}
+
+	for _, t := range transitions {
+		entryTitle := fmt.Sprintf("%s: %s", t.Title, t.Level)
+		if t.PreviousLevel != "" {
+			entryTitle = fmt.Sprintf("%s: %s → %s", t.Title, t.PreviousLevel, t.Level)
+		}
+		doc.Entries = append(doc.Entries, atomEntry{
+			ID:      fmt.Sprintf("%s/feeds/%s/%d", f.baseURL, t.Key, t.ChangedAt.Unix()),
+			Title:   entryTitle,
+			Updated: t.ChangedAt.UTC().Format(time.RFC3339),
+			Content: atomContent{Type: "text", Body: t.Status},
+		})
+	}
+
+	data, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshaling feed: %w", err)
+	}
+	return append([]byte(xml.Header), data...), nil
+}
+
+// ServeHTTP serves the combined feed at /api/feed and per-monitor feeds at
+// /api/feed/<key>
+func (f *AtomFeed) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	key := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/api/feed"), "/")
+	key = strings.TrimSuffix(key, ".atom")
+
+	if key != "" {
+		f.mu.Lock()
+		_, ok := f.transitions[key]
+		f.mu.Unlock()
+		if !ok {
+			http.Error(w, "monitor not found", http.StatusNotFound)
+			return
+		}
+	}
+
+	data, err := f.Render(key)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", atomContentType)
+	_, _ = w.Write(data)
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"feed"`
+	Xmlns   string      `xml:"xmlns,attr"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Author  atomAuthor  `xml:"author"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr"`
+	Href string `xml:"href,attr"`
+}
+
+type atomEntry struct {
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Content atomContent `xml:"content"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
@@ -0,0 +1,126 @@
+package feed
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/weebcast/weebcast-operator/pkg/webhook"
+)
+
+var feedStart = time.Date(2026, 10, 5, 0, 0, 0, 0, time.UTC)
+
+// pushLevel pushes a level change of key, hours after feedStart
+func pushLevel(t *testing.T, f *AtomFeed, key, level string, hours int) {
+	t.Helper()
+	changedAt := feedStart.Add(time.Duration(hours) * time.Hour)
+	payload := &webhook.ActivityPayload{
+		AnimeName:          "Show " + key,
+		ActivityLevel:      level,
+		WeebcastStatus:     level + " weather",
+		LastActivityChange: &changedAt,
+	}
+	if err := f.PushActivity(context.Background(), key, payload); err != nil {
+		t.Fatalf("PushActivity(%s, %s) error = %v", key, level, err)
+	}
+}
+
+// entryTitles returns the entry titles of a rendered feed, newest first
+func entryTitles(document string) []string {
+	var titles []string
+	for _, line := range strings.Split(document, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "<title>Show") {
+			titles = append(titles, strings.TrimSuffix(strings.TrimPrefix(line, "<title>"), "</title>"))
+		}
+	}
+	return titles
+}
+
+func TestAtomFeedReload(t *testing.T) {
+	recorder := &documentRecorder{}
+	before := NewAtomFeed("https://weebcast.com").WithDocumentPublishers(recorder)
+	pushLevel(t, before, "frieren", "Low", 0)
+	pushLevel(t, before, "frieren", "High", 1)
+	pushLevel(t, before, "dandadan", "Low", 2)
+	pushLevel(t, before, "dandadan", "Medium", 3)
+
+	// After a restart, an unchanged level writes nothing
+	after := NewAtomFeed("https://weebcast.com").WithDocumentPublishers(recorder)
+	writes := recorder.writes
+	pushLevel(t, after, "frieren", "High", 1)
+	if recorder.writes != writes {
+		t.Errorf("unchanged level wrote %d documents", recorder.writes-writes)
+	}
+
+	// A change continues the published feeds
+	pushLevel(t, after, "frieren", "Critical", 4)
+
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{
+			path: "feeds/frieren.atom",
+			want: []string{"Show frieren: High → Critical", "Show frieren: Low → High", "Show frieren: Low"},
+		},
+		{
+			path: "feeds/activity.atom",
+			want: []string{
+				"Show frieren: High → Critical",
+				"Show dandadan: Low → Medium",
+				"Show dandadan: Low",
+				"Show frieren: Low → High",
+				"Show frieren: Low",
+			},
+		},
+	}
+	for _, tt := range tests {
+		got := entryTitles(recorder.documents[tt.path])
+		if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+			t.Errorf("%s entries = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+
+	transitions, err := parseTransitions([]byte(recorder.documents["feeds/frieren.atom"]))
+	if err != nil {
+		t.Fatalf("parseTransitions() error = %v", err)
+	}
+	last := transitions[len(transitions)-1]
+	if last.Key != "frieren" || last.PreviousLevel != "High" || last.Level != "Critical" ||
+		last.Status != "Critical weather" || !last.ChangedAt.Equal(feedStart.Add(4*time.Hour)) {
+		t.Errorf("last transition = %+v", last)
+	}
+}
+
+func TestAtomFeedRemoveActivity(t *testing.T) {
+	ctx := context.Background()
+	recorder := &documentRecorder{}
+	f := NewAtomFeed("https://weebcast.com").WithDocumentPublishers(recorder)
+	pushLevel(t, f, "frieren", "Low", 0)
+	pushLevel(t, f, "frieren", "High", 1)
+	pushLevel(t, f, "dandadan", "Low", 2)
+	pushLevel(t, f, "dandadan", "Medium", 3)
+
+	if err := f.RemoveActivity(ctx, "frieren"); err != nil {
+		t.Fatalf("RemoveActivity() error = %v", err)
+	}
+
+	if _, ok := recorder.documents["feeds/frieren.atom"]; ok {
+		t.Error("feeds/frieren.atom was not deleted")
+	}
+	want := []string{"Show dandadan: Low → Medium", "Show dandadan: Low"}
+	if got := entryTitles(recorder.documents["feeds/activity.atom"]); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("combined feed entries = %q, want %q", got, want)
+	}
+
+	// After a restart, a monitor recreated under the same key starts afresh
+	after := NewAtomFeed("https://weebcast.com").WithDocumentPublishers(recorder)
+	pushLevel(t, after, "frieren", "Critical", 4)
+	pushLevel(t, after, "frieren", "Low", 5)
+	want = []string{"Show frieren: Critical → Low", "Show frieren: Critical"}
+	if got := entryTitles(recorder.documents["feeds/frieren.atom"]); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("recreated feed entries = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,317 @@
+package feed
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/weebcast/weebcast-operator/pkg/webhook"
+)
+
+const (
+	// calendarWeeks is how far ahead upcoming episodes are listed
+	calendarWeeks = 4
+
+	// episodeDuration is the assumed runtime of an episode
+	episodeDuration = 30 * time.Minute
+
+	// calendarContentType is the media type of iCalendar documents
+	calendarContentType = "text/calendar; charset=utf-8"
+)
+
+// broadcastDays maps Jikan broadcast days to weekdays
+var broadcastDays = map[string]time.Weekday{
+	"Sundays":    time.Sunday,
+	"Mondays":    time.Monday,
+	"Tuesdays":   time.Tuesday,
+	"Wednesdays": time.Wednesday,
+	"Thursdays":  time.Thursday,
+	"Fridays":    time.Friday,
+	"Saturdays":  time.Saturday,
+}
+
+// Episode is a single upcoming broadcast of a monitored anime
+type Episode struct {
+	Key      string
+	Title    string
+	AiringAt time.Time
+	Level    string
+	Status   string
+}
+
+// EpisodeCalendar renders upcoming episode air times of every monitored
+// airing anime as an iCalendar feed, with the forecasted activity level in
+// each event's description. It implements webhook.ActivityRemover so it
+// always reflects the latest reconcile and drops deleted monitors.
+type EpisodeCalendar struct {
+	documents []webhook.DocumentPublisher
+
+	mu       sync.Mutex
+	payloads map[string]*webhook.ActivityPayload
+
+	// written is the episode list last written to the document publishers
+	written []Episode
+}
+
+// NewEpisodeCalendar creates a new episode calendar
+func NewEpisodeCalendar() *EpisodeCalendar {
+	return &EpisodeCalendar{
+		payloads: make(map[string]*webhook.ActivityPayload),
+	}
+}
+
+// WithDocumentPublishers writes the calendar to the given publishers
+// whenever the upcoming episodes or their forecasts change
+func (c *EpisodeCalendar) WithDocumentPublishers(publishers ...webhook.DocumentPublisher) *EpisodeCalendar {
+	c.documents = append(c.documents, publishers...)
+	return c
+}
+
+// Name identifies the calendar in logs
+func (c *EpisodeCalendar) Name() string {
+	return "episode-calendar"
+}
+
+// PushActivity records the monitor's broadcast slot and forecast
+func (c *EpisodeCalendar) PushActivity(ctx context.Context, key string, payload *webhook.ActivityPayload) error {
+	c.mu.Lock()
+	if payload.Broadcast == nil {
+		delete(c.payloads, key)
+	} else {
+		c.payloads[key] = payload
+	}
+	c.mu.Unlock()
+
+	return c.publish(ctx, time.Now())
+}
+
+// RemoveActivity drops a deleted monitor's episodes and rewrites the calendar
+func (c *EpisodeCalendar) RemoveActivity(ctx context.Context, key string) error {
+	c.mu.Lock()
+	delete(c.payloads, key)
+	c.mu.Unlock()
+
+	return c.publish(ctx, time.Now())
+}
+
+// publish writes the calendar to the document publishers when the upcoming
+// episodes differ from the ones last written, whether because a monitor
+// changed or because an episode has aired since
+func (c *EpisodeCalendar) publish(ctx context.Context, now time.Time) error {
+	if len(c.documents) == 0 {
+		return nil
+	}
+
+	episodes := c.Episodes(now)
+	c.mu.Lock()
+	changed := !sameEpisodes(c.written, episodes)
+	c.mu.Unlock()
+	if !changed {
+		return nil
+	}
+
+	data := c.render(now, episodes)
+	for _, publisher := range c.documents {
+		if err := publisher.PutDocument(ctx, "feeds/episodes.ics", calendarContentType, data); err != nil {
+			return fmt.Errorf("writing calendar to %s: %w", publisher.Name(), err)
+		}
+	}
+
+	c.mu.Lock()
+	c.written = episodes
+	c.mu.Unlock()
+	return nil
+}
+
+// sameEpisodes reports whether two episode lists render the same events.
+// Status is ignored, as it changes with every poll while the level holds.
+func sameEpisodes(a, b []Episode) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Key != b[i].Key || a[i].Title != b[i].Title || a[i].Level != b[i].Level || !a[i].AiringAt.Equal(b[i].AiringAt) {
+			return false
+		}
+	}
+	return true
+}
+
+// Episodes returns the upcoming episodes of all monitored anime, soonest first
+func (c *EpisodeCalendar) Episodes(now time.Time) []Episode {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var episodes []Episode
+	for key, payload := range c.payloads {
+		first, ok := nextBroadcast(payload.Broadcast, now)
+		if !ok {
+			continue
+		}
+
+		title := payload.AnimeName
+		if title == "" {
+			title = payload.MonitorName
+		}
+
+		weeks := calendarWeeks
+		if remaining, ok := remainingEpisodes(payload.Broadcast, first); ok && remaining < weeks {
+			weeks = remaining
+		}
+
+		for week := 0; week < weeks; week++ {
+			episodes = append(episodes, Episode{
+				Key:      key,
+				Title:    title,
+				AiringAt: first.AddDate(0, 0, 7*week),
+				Level:    payload.ActivityLevel,
+				Status:   payload.WeebcastStatus,
+			})
+		}
+	}
+
+	sort.Slice(episodes, func(i, j int) bool {
+		if episodes[i].AiringAt.Equal(episodes[j].AiringAt) {
+			return episodes[i].Key < episodes[j].Key
+		}
+		return episodes[i].AiringAt.Before(episodes[j].AiringAt)
+	})
+	return episodes
+}
+
+// remainingEpisodes returns how many episodes are left from the broadcast at
+// next onwards, counting weekly broadcasts since the premiere. It is false
+// when MAL does not know the episode count or premiere date.
+func remainingEpisodes(slot *webhook.BroadcastPayload, next time.Time) (int, bool) {
+	if slot.Episodes <= 0 || slot.Premiered == nil {
+		return 0, false
+	}
+
+	// MAL reports the premiere as a date in the broadcast's timezone, so
+	// the first episode is the first slot from midnight of that date
+	year, month, day := slot.Premiered.Date()
+	midnight := time.Date(year, month, day, 0, 0, 0, 0, next.Location())
+	first, ok := nextBroadcast(slot, midnight.Add(-time.Second))
+	if !ok || first.After(next) {
+		return slot.Episodes, true
+	}
+
+	aired := int(math.Round(next.Sub(first).Hours() / (7 * 24)))
+	if aired >= slot.Episodes {
+		return 0, true
+	}
+	return slot.Episodes - aired, true
+}
+
+// nextBroadcast returns the next broadcast after now in the slot's timezone
+func nextBroadcast(slot *webhook.BroadcastPayload, now time.Time) (time.Time, bool) {
+	weekday, ok := broadcastDays[slot.Day]
+	if !ok {
+		return time.Time{}, false
+	}
+
+	var hour, minute int
+	if _, err := fmt.Sscanf(slot.Time, "%d:%d", &hour, &minute); err != nil {
+		return time.Time{}, false
+	}
+
+	loc := time.UTC
+	if slot.Timezone != "" {
+		l, err := time.LoadLocation(slot.Timezone)
+		if err != nil {
+			return time.Time{}, false
+		}
+		loc = l
+	}
+
+	local := now.In(loc)
+	next := time.Date(local.Year(), local.Month(), local.Day(), hour, minute, 0, 0, loc)
+	next = next.AddDate(0, 0, (int(weekday)-int(next.Weekday())+7)%7)
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 7)
+	}
+	return next, true
+}
+
+// Render returns the iCalendar document of upcoming episodes
+func (c *EpisodeCalendar) Render(now time.Time) []byte {
+	return c.render(now, c.Episodes(now))
+}
+
+// render returns the iCalendar document of the given episodes
+func (c *EpisodeCalendar) render(now time.Time, episodes []Episode) []byte {
+	var b strings.Builder
+	writeLine := func(line string) {
+		b.WriteString(foldLine(line))
+		b.WriteString("\r\n")
+	}
+
+	stamp := now.UTC().Format("20060102T150405Z")
+
+	writeLine("BEGIN:VCALENDAR")
+	writeLine("VERSION:2.0")
+	writeLine("PRODID:-//weebcast.com//Weebcast Operator//EN")
+	writeLine("CALSCALE:GREGORIAN")
+	writeLine("METHOD:PUBLISH")
+	writeLine("X-WR-CALNAME:Weebcast Episode Forecast")
+
+	for _, episode := range episodes {
+		start := episode.AiringAt.UTC()
+		writeLine("BEGIN:VEVENT")
+		writeLine(fmt.Sprintf("UID:%s-%s@weebcast.com", episode.Key, start.Format("20060102T1504Z")))
+		writeLine("DTSTAMP:" + stamp)
+		writeLine("DTSTART:" + start.Format("20060102T150405Z"))
+		writeLine("DTEND:" + start.Add(episodeDuration).Format("20060102T150405Z"))
+		writeLine("SUMMARY:" + escapeText(episode.Title+" - new episode"))
+		writeLine("DESCRIPTION:" + escapeText(fmt.Sprintf("Forecast: %s\n\n%s", episode.Level, episode.Status)))
+		writeLine("CATEGORIES:" + escapeText(episode.Level))
+		writeLine("END:VEVENT")
+	}
+
+	writeLine("END:VCALENDAR")
+	return []byte(b.String())
+}
+
+// ServeHTTP serves the calendar
+func (c *EpisodeCalendar) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", calendarContentType)
+	_, _ = w.Write(c.Render(time.Now()))
+}
+
+// escapeText escapes a value for an iCalendar TEXT property
+func escapeText(value string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(value)
+}
+
+// foldLine folds content lines longer than 75 octets without splitting
+// multi-byte characters
+func foldLine(line string) string {
+	const limit = 75
+	if len(line) <= limit {
+		return line
+	}
+
+	var b strings.Builder
+	width := 0
+	for _, r := range line {
+		size := len(string(r))
+		if width+size > limit {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	return b.String()
+}
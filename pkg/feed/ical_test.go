@@ -0,0 +1,136 @@
+package feed
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/weebcast/weebcast-operator/pkg/webhook"
+)
+
+// documentRecorder is a DocumentReader and DocumentRemover that keeps the
+// last write of each path
+type documentRecorder struct {
+	writes    int
+	documents map[string]string
+}
+
+func (d *documentRecorder) Name() string { return "recorder" }
+
+func (d *documentRecorder) PushActivity(context.Context, string, *webhook.ActivityPayload) error {
+	return nil
+}
+
+func (d *documentRecorder) PutDocument(_ context.Context, path, _ string, data []byte) error {
+	if d.documents == nil {
+		d.documents = make(map[string]string)
+	}
+	d.writes++
+	d.documents[path] = string(data)
+	return nil
+}
+
+func (d *documentRecorder) GetDocument(_ context.Context, path string) ([]byte, error) {
+	data, ok := d.documents[path]
+	if !ok {
+		return nil, webhook.ErrDocumentNotFound
+	}
+	return []byte(data), nil
+}
+
+func (d *documentRecorder) DeleteDocument(_ context.Context, path string) error {
+	delete(d.documents, path)
+	return nil
+}
+
+func TestEpisodeCalendarRewrites(t *testing.T) {
+	premiered := time.Date(2026, 10, 3, 0, 0, 0, 0, time.UTC)
+	payload := func(level, status string) *webhook.ActivityPayload {
+		return &webhook.ActivityPayload{
+			AnimeName:      "Frieren",
+			ActivityLevel:  level,
+			WeebcastStatus: status,
+			Broadcast: &webhook.BroadcastPayload{
+				Day:       "Saturdays",
+				Time:      "23:00",
+				Timezone:  "UTC",
+				Episodes:  6,
+				Premiered: &premiered,
+			},
+		}
+	}
+	day := func(month time.Month, day int) time.Time {
+		return time.Date(2026, month, day, 12, 0, 0, 0, time.UTC)
+	}
+
+	recorder := &documentRecorder{}
+	calendar := NewEpisodeCalendar().WithDocumentPublishers(recorder)
+
+	steps := []struct {
+		name    string
+		now     time.Time
+		payload *webhook.ActivityPayload
+		writes  int
+		// first is the first listed episode, zero when none are left
+		first    time.Time
+		episodes int
+	}{
+		{name: "first push", now: day(10, 5), payload: payload("Low", "calm"), writes: 1, first: day(10, 10), episodes: 4},
+		{name: "status only", now: day(10, 6), payload: payload("Low", "still calm"), writes: 1, first: day(10, 10), episodes: 4},
+		{name: "level change", now: day(10, 6), payload: payload("High", "storm"), writes: 2, first: day(10, 10), episodes: 4},
+		{name: "episode aired", now: day(10, 11), payload: payload("High", "storm"), writes: 3, first: day(10, 17), episodes: 4},
+		{name: "same week", now: day(10, 12), payload: payload("High", "storm"), writes: 3, first: day(10, 17), episodes: 4},
+		{name: "final episodes", now: day(10, 25), payload: payload("High", "storm"), writes: 4, first: day(10, 31), episodes: 2},
+		{name: "finished", now: day(11, 8), payload: payload("High", "storm"), writes: 5, episodes: 0},
+	}
+
+	for _, step := range steps {
+		calendar.payloads["default/frieren"] = step.payload
+		if err := calendar.publish(context.Background(), step.now); err != nil {
+			t.Fatalf("%s: publish() error = %v", step.name, err)
+		}
+
+		if recorder.writes != step.writes {
+			t.Errorf("%s: %d writes, want %d", step.name, recorder.writes, step.writes)
+		}
+		document := recorder.documents["feeds/episodes.ics"]
+		if got := strings.Count(document, "BEGIN:VEVENT"); got != step.episodes {
+			t.Errorf("%s: %d episodes written, want %d", step.name, got, step.episodes)
+		}
+		if !step.first.IsZero() {
+			start := "DTSTART:" + step.first.Add(11*time.Hour).Format("20060102T150405Z")
+			if i := strings.Index(document, "DTSTART:"); i < 0 || !strings.HasPrefix(document[i:], start) {
+				t.Errorf("%s: calendar does not start with %s:\n%s", step.name, start, document)
+			}
+		}
+	}
+}
+
+func TestEpisodeCalendarRemoveActivity(t *testing.T) {
+	ctx := context.Background()
+	recorder := &documentRecorder{}
+	calendar := NewEpisodeCalendar().WithDocumentPublishers(recorder)
+
+	for _, key := range []string{"default/frieren", "default/dandadan"} {
+		payload := &webhook.ActivityPayload{
+			AnimeName:     key,
+			ActivityLevel: "Low",
+			Broadcast:     &webhook.BroadcastPayload{Day: "Saturdays", Time: "23:00", Timezone: "UTC"},
+		}
+		if err := calendar.PushActivity(ctx, key, payload); err != nil {
+			t.Fatalf("PushActivity(%s) error = %v", key, err)
+		}
+	}
+
+	if err := calendar.RemoveActivity(ctx, "default/frieren"); err != nil {
+		t.Fatalf("RemoveActivity() error = %v", err)
+	}
+	document := recorder.documents["feeds/episodes.ics"]
+	if strings.Contains(document, "default/frieren") {
+		t.Errorf("removed monitor still listed:\n%s", document)
+	}
+	if got := strings.Count(document, "BEGIN:VEVENT"); got != calendarWeeks {
+		t.Errorf("%d episodes written, want %d", got, calendarWeeks)
+	}
+}
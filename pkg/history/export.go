@@ -0,0 +1,267 @@
+package history
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Export formats
+const (
+	FormatCSV   = "csv"
+	FormatJSONL = "jsonl"
+)
+
+// csvHeader lists the exported columns in order
+var csvHeader = []string{
+	"timestamp", "monitor", "resolution",
+	"activeUsers", "watchingCount", "completedCount", "droppedCount", "planToWatchCount",
+	"score", "scoredByCount", "rank", "popularity", "members", "favorites",
+	"activityScore", "level",
+}
+
+// ExportQuery selects the samples to export
+type ExportQuery struct {
+	// Monitor is the "namespace/name" of the monitor to export; empty exports all monitors
+	Monitor string
+	// From and To bound the exported time range; zero values leave that end open
+	From time.Time
+	To   time.Time
+	// Format is FormatCSV or FormatJSONL
+	Format string
+}
+
+// exportRow is a sample tagged with its monitor for JSON Lines output
+type exportRow struct {
+	Monitor string `json:"monitor"`
+	Sample
+}
+
+// Export writes the selected samples to w one row at a time, so large
+// exports are streamed rather than loaded into memory
+func (s *Store) Export(w io.Writer, query ExportQuery) error {
+	var write func(monitor string, sample Sample) error
+	var flush func() error
+
+	switch query.Format {
+	case FormatCSV, "":
+		cw := csv.NewWriter(w)
+		if err := cw.Write(csvHeader); err != nil {
+			return fmt.Errorf("writing header: %w", err)
+		}
+		write = func(monitor string, sample Sample) error {
+			return cw.Write(csvRecord(monitor, sample))
+		}
+		flush = func() error {
+			cw.Flush()
+			return cw.Error()
+		}
+	case FormatJSONL:
+		enc := json.NewEncoder(w)
+		write = func(monitor string, sample Sample) error {
+			return enc.Encode(exportRow{Monitor: monitor, Sample: sample})
+		}
+		flush = func() error { return nil }
+	default:
+		return fmt.Errorf("unsupported export format %q", query.Format)
+	}
+
+	monitors := []string{query.Monitor}
+	if query.Monitor == "" {
+		var err error
+		monitors, err = s.Monitors()
+		if err != nil {
+			return fmt.Errorf("listing monitors: %w", err)
+		}
+		sort.Strings(monitors)
+	}
+
+	for _, monitor := range monitors {
+		err := s.Range(monitor, query.From, query.To, func(sample Sample) error {
+			return write(monitor, sample)
+		})
+		if err != nil {
+			return fmt.Errorf("exporting %s: %w", monitor, err)
+		}
+	}
+
+	return flush()
+}
+
+// csvRecord formats a sample as a CSV row matching csvHeader
+func csvRecord(monitor string, sample Sample) []string {
+	return []string{
+		sample.Timestamp.UTC().Format(time.RFC3339),
+		monitor,
+		sample.Resolution,
+		strconv.Itoa(sample.ActiveUsers),
+		strconv.Itoa(sample.WatchingCount),
+		strconv.Itoa(sample.CompletedCount),
+		strconv.Itoa(sample.DroppedCount),
+		strconv.Itoa(sample.PlanToWatchCount),
+		strconv.FormatFloat(sample.Score, 'f', -1, 64),
+		strconv.Itoa(sample.ScoredByCount),
+		strconv.Itoa(sample.Rank),
+		strconv.Itoa(sample.Popularity),
+		strconv.Itoa(sample.Members),
+		strconv.Itoa(sample.Favorites),
+		strconv.Itoa(sample.ActivityScore),
+		sample.Level,
+	}
+}
+
+// ReadExport reads samples written by Export, calling fn with each in file
+// order. CSV columns are matched by header name, so columns may be reordered
+// or dropped.
+func ReadExport(r io.Reader, format string, fn func(monitor string, sample Sample) error) error {
+	switch format {
+	case FormatCSV, "":
+		return readCSV(r, fn)
+	case FormatJSONL:
+		dec := json.NewDecoder(r)
+		for line := 1; ; line++ {
+			var row exportRow
+			if err := dec.Decode(&row); err == io.EOF {
+				return nil
+			} else if err != nil {
+				return fmt.Errorf("reading row %d: %w", line, err)
+			}
+			if err := fn(row.Monitor, row.Sample); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported export format %q", format)
+	}
+}
+
+// readCSV reads a CSV export
+func readCSV(r io.Reader, fn func(monitor string, sample Sample) error) error {
+	cr := csv.NewReader(r)
+	header, err := cr.Read()
+	if err != nil {
+		return fmt.Errorf("reading header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[name] = i
+	}
+	if _, ok := columns["timestamp"]; !ok {
+		return fmt.Errorf("missing timestamp column")
+	}
+
+	for line := 2; ; line++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("reading line %d: %w", line, err)
+		}
+
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return record[i]
+			}
+			return ""
+		}
+		integer := func(name string) int {
+			v, _ := strconv.Atoi(field(name))
+			return v
+		}
+
+		timestamp, err := time.Parse(time.RFC3339, field("timestamp"))
+		if err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+		score, _ := strconv.ParseFloat(field("score"), 64)
+
+		sample := Sample{
+			Timestamp:        timestamp,
+			Resolution:       field("resolution"),
+			ActiveUsers:      integer("activeUsers"),
+			WatchingCount:    integer("watchingCount"),
+			CompletedCount:   integer("completedCount"),
+			DroppedCount:     integer("droppedCount"),
+			PlanToWatchCount: integer("planToWatchCount"),
+			Score:            score,
+			ScoredByCount:    integer("scoredByCount"),
+			Rank:             integer("rank"),
+			Popularity:       integer("popularity"),
+			Members:          integer("members"),
+			Favorites:        integer("favorites"),
+			ActivityScore:    integer("activityScore"),
+			Level:            field("level"),
+		}
+		if err := fn(field("monitor"), sample); err != nil {
+			return err
+		}
+	}
+}
+
+// ParseTime parses an export bound given as RFC 3339 or a plain date
+func ParseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q: expected RFC 3339 or YYYY-MM-DD", value)
+	}
+	return t, nil
+}
+
+// ServeHTTP streams an export at /api/history/export. Query parameters:
+// monitor (namespace/name, default all), from, to and format (csv or jsonl).
+func (s *Store) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+
+	from, err := ParseTime(params.Get("from"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	to, err := ParseTime(params.Get("to"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	query := ExportQuery{
+		Monitor: params.Get("monitor"),
+		From:    from,
+		To:      to,
+		Format:  params.Get("format"),
+	}
+
+	switch query.Format {
+	case FormatCSV, "":
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", `attachment; filename="history.csv"`)
+	case FormatJSONL:
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.Header().Set("Content-Disposition", `attachment; filename="history.jsonl"`)
+	default:
+		http.Error(w, fmt.Sprintf("unsupported export format %q", query.Format), http.StatusBadRequest)
+		return
+	}
+
+	// Headers are already sent once rows are streamed, so a failure part
+	// way through is logged and the connection aborted, which clients see
+	// as an incomplete response rather than a complete export
+	if err := s.Export(w, query); err != nil {
+		log.FromContext(r.Context()).WithName("history").Error(err, "History export failed",
+			"monitor", query.Monitor, "from", params.Get("from"), "to", params.Get("to"))
+		panic(http.ErrAbortHandler)
+	}
+}
@@ -0,0 +1,353 @@
+package history
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Resolutions of stored samples
+const (
+	ResolutionRaw    = "raw"
+	ResolutionHourly = "1h"
+)
+
+// ErrStopIteration can be returned from a Range callback to stop early without error
+var ErrStopIteration = errors.New("stop iteration")
+
+// Sample is a single stored observation of a monitor
+type Sample struct {
+	Timestamp        time.Time `json:"timestamp"`
+	Resolution       string    `json:"resolution"`
+	ActiveUsers      int       `json:"activeUsers"`
+	WatchingCount    int       `json:"watchingCount"`
+	CompletedCount   int       `json:"completedCount"`
+	DroppedCount     int       `json:"droppedCount"`
+	PlanToWatchCount int       `json:"planToWatchCount"`
+	Score            float64   `json:"score"`
+	ScoredByCount    int       `json:"scoredByCount"`
+	Rank             int       `json:"rank"`
+	Popularity       int       `json:"popularity"`
+	Members          int       `json:"members"`
+	Favorites        int       `json:"favorites"`
+	ActivityScore    int       `json:"activityScore"`
+	Level            string    `json:"level"`
+}
+
+// Policy controls how long samples are kept and when they are downsampled
+type Policy struct {
+	// Retention is how long samples are kept before being deleted
+	Retention time.Duration
+	// DownsampleAfter is the age after which raw samples are merged into hourly samples
+	DownsampleAfter time.Duration
+	// CompactInterval is how often retention and downsampling are applied
+	CompactInterval time.Duration
+}
+
+// DefaultPolicy keeps a little over a year of data, enough to compare a
+// season's premiere with the same season a year earlier, at hourly
+// resolution after the first week
+var DefaultPolicy = Policy{
+	Retention:       400 * 24 * time.Hour,
+	DownsampleAfter: 7 * 24 * time.Hour,
+	CompactInterval: time.Hour,
+}
+
+// Store is an embedded on-disk time-series store of monitor samples,
+// backed by a bolt database with one bucket per monitor keyed by time
+type Store struct {
+	db     *bolt.DB
+	policy Policy
+}
+
+// Open opens (or creates) the store at path
+func Open(path string, policy Policy) (*Store, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("opening history database: %w", err)
+	}
+	return &Store{db: db, policy: policy}, nil
+}
+
+// OpenReadOnly opens an existing store for reading, e.g. to export it. The
+// operator holds an exclusive lock while running, so this waits up to the
+// open timeout and fails if the store is still in use.
+func OpenReadOnly(path string) (*Store, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: 5 * time.Second, ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("opening history database: %w", err)
+	}
+	return &Store{db: db}, nil
+}
+
+// Close closes the underlying database
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Append stores a raw sample for a monitor ("namespace/name")
+func (s *Store) Append(monitor string, sample Sample) error {
+	if sample.Resolution == "" {
+		sample.Resolution = ResolutionRaw
+	}
+	data, err := json.Marshal(sample)
+	if err != nil {
+		return fmt.Errorf("marshaling sample: %w", err)
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists([]byte(monitor))
+		if err != nil {
+			return fmt.Errorf("creating bucket for %s: %w", monitor, err)
+		}
+		return bucket.Put(timeKey(sample.Timestamp), data)
+	})
+}
+
+// Monitors returns the names of all monitors with stored samples
+func (s *Store) Monitors() ([]string, error) {
+	var monitors []string
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.ForEach(func(name []byte, _ *bolt.Bucket) error {
+			monitors = append(monitors, string(name))
+			return nil
+		})
+	})
+	return monitors, err
+}
+
+// rangePageSize is the number of samples Range reads per transaction
+const rangePageSize = 512
+
+// Range calls fn for each sample of a monitor in [from, to), oldest first.
+// A zero from or to leaves that end of the range open. Samples are read in
+// pages of bounded read transactions and fn is called between them, so a
+// slow consumer, such as an export to a slow client, does not hold a
+// transaction open for the whole range.
+func (s *Store) Range(monitor string, from, to time.Time, fn func(Sample) error) error {
+	// last is the key of the last sample read, where the next page resumes
+	var last []byte
+
+	for {
+		page := make([]Sample, 0, rangePageSize)
+		err := s.db.View(func(tx *bolt.Tx) error {
+			bucket := tx.Bucket([]byte(monitor))
+			if bucket == nil {
+				return nil
+			}
+
+			cursor := bucket.Cursor()
+			var k, v []byte
+			switch {
+			case last != nil:
+				if k, v = cursor.Seek(last); bytes.Equal(k, last) {
+					k, v = cursor.Next()
+				}
+			case from.IsZero():
+				k, v = cursor.First()
+			default:
+				k, v = cursor.Seek(timeKey(from))
+			}
+
+			for ; k != nil && len(page) < rangePageSize; k, v = cursor.Next() {
+				if !to.IsZero() && !keyTime(k).Before(to) {
+					break
+				}
+				var sample Sample
+				if err := json.Unmarshal(v, &sample); err != nil {
+					return fmt.Errorf("decoding sample: %w", err)
+				}
+				page = append(page, sample)
+				// Keys are only valid within the transaction
+				last = append(last[:0], k...)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, sample := range page {
+			if err := fn(sample); err != nil {
+				if errors.Is(err, ErrStopIteration) {
+					return nil
+				}
+				return err
+			}
+		}
+		if len(page) < rangePageSize {
+			return nil
+		}
+	}
+}
+
+// Start applies the retention and downsampling policy periodically until
+// the context is cancelled, then closes the store. It implements manager.Runnable.
+func (s *Store) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("history")
+
+	interval := s.policy.CompactInterval
+	if interval <= 0 {
+		interval = DefaultPolicy.CompactInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Compact(time.Now()); err != nil {
+			logger.Error(err, "Failed to compact history")
+		}
+
+		select {
+		case <-ctx.Done():
+			return s.Close()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Compact deletes samples older than the retention period and merges raw
+// samples older than DownsampleAfter into hourly samples
+func (s *Store) Compact(now time.Time) error {
+	monitors, err := s.Monitors()
+	if err != nil {
+		return err
+	}
+
+	for _, monitor := range monitors {
+		if err := s.compactMonitor(monitor, now); err != nil {
+			return fmt.Errorf("compacting %s: %w", monitor, err)
+		}
+	}
+	return nil
+}
+
+// compactMonitor applies the policy to a single monitor's bucket
+func (s *Store) compactMonitor(monitor string, now time.Time) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(monitor))
+		if bucket == nil {
+			return nil
+		}
+
+		// Drop everything past retention
+		if s.policy.Retention > 0 {
+			cutoff := timeKey(now.Add(-s.policy.Retention))
+			cursor := bucket.Cursor()
+			for k, _ := cursor.First(); k != nil && string(k) < string(cutoff); k, _ = cursor.Next() {
+				if err := cursor.Delete(); err != nil {
+					return err
+				}
+			}
+		}
+
+		if s.policy.DownsampleAfter <= 0 {
+			return nil
+		}
+
+		// Group raw samples older than the downsample cutoff by hour
+		cutoff := now.Add(-s.policy.DownsampleAfter).Truncate(time.Hour)
+		groups := make(map[time.Time][]Sample)
+		var stale [][]byte
+
+		cursor := bucket.Cursor()
+		for k, v := cursor.First(); k != nil && keyTime(k).Before(cutoff); k, v = cursor.Next() {
+			var sample Sample
+			if err := json.Unmarshal(v, &sample); err != nil {
+				return fmt.Errorf("decoding sample: %w", err)
+			}
+			if sample.Resolution != ResolutionRaw {
+				continue
+			}
+			hour := sample.Timestamp.Truncate(time.Hour)
+			groups[hour] = append(groups[hour], sample)
+			stale = append(stale, append([]byte(nil), k...))
+		}
+
+		for _, k := range stale {
+			if err := bucket.Delete(k); err != nil {
+				return err
+			}
+		}
+
+		hours := make([]time.Time, 0, len(groups))
+		for hour := range groups {
+			hours = append(hours, hour)
+		}
+		sort.Slice(hours, func(i, j int) bool { return hours[i].Before(hours[j]) })
+
+		for _, hour := range hours {
+			data, err := json.Marshal(downsample(hour, groups[hour]))
+			if err != nil {
+				return fmt.Errorf("marshaling sample: %w", err)
+			}
+			if err := bucket.Put(timeKey(hour), data); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// downsample merges samples into a single hourly sample. Counts and scores
+// are averaged; the level is taken from the sample with the highest
+// activity score so short storms remain visible.
+func downsample(hour time.Time, samples []Sample) Sample {
+	merged := Sample{Timestamp: hour, Resolution: ResolutionHourly}
+	n := len(samples)
+	peak := samples[0]
+
+	for _, sample := range samples {
+		merged.ActiveUsers += sample.ActiveUsers
+		merged.WatchingCount += sample.WatchingCount
+		merged.CompletedCount += sample.CompletedCount
+		merged.DroppedCount += sample.DroppedCount
+		merged.PlanToWatchCount += sample.PlanToWatchCount
+		merged.Score += sample.Score
+		merged.ScoredByCount += sample.ScoredByCount
+		merged.Rank += sample.Rank
+		merged.Popularity += sample.Popularity
+		merged.Members += sample.Members
+		merged.Favorites += sample.Favorites
+		merged.ActivityScore += sample.ActivityScore
+		if sample.ActivityScore > peak.ActivityScore {
+			peak = sample
+		}
+	}
+
+	merged.ActiveUsers /= n
+	merged.WatchingCount /= n
+	merged.CompletedCount /= n
+	merged.DroppedCount /= n
+	merged.PlanToWatchCount /= n
+	merged.Score /= float64(n)
+	merged.ScoredByCount /= n
+	merged.Rank /= n
+	merged.Popularity /= n
+	merged.Members /= n
+	merged.Favorites /= n
+	merged.ActivityScore /= n
+	merged.Level = peak.Level
+
+	return merged
+}
+
+// timeKey encodes a timestamp as a big-endian key so keys sort by time
+func timeKey(t time.Time) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, uint64(t.UnixNano()))
+	return key
+}
+
+// keyTime decodes a key written by timeKey
+func keyTime(key []byte) time.Time {
+	return time.Unix(0, int64(binary.BigEndian.Uint64(key)))
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 )
 
@@ -24,6 +25,13 @@ func NewClient() *Client {
 	}
 }
 
+// WithBaseURL points the client at a different Jikan endpoint, such as a
+// local stand-in server
+func (c *Client) WithBaseURL(baseURL string) *Client {
+	c.baseURL = strings.TrimSuffix(baseURL, "/")
+	return c
+}
+
 // AnimeData represents anime information from MAL
 type AnimeData struct {
 	MalID        int    `json:"mal_id"`
@@ -45,9 +53,26 @@ type AnimeData struct {
 	Favorites  int              `json:"favorites"`
 	Status     string           `json:"status"`
 	Airing     bool             `json:"airing"`
+	Episodes   int              `json:"episodes"`
+	Aired      Aired            `json:"aired"`
+	Broadcast  Broadcast        `json:"broadcast"`
 	Statistics *AnimeStatistics `json:"statistics,omitempty"`
 }
 
+// Aired is the date range an anime aired over
+type Aired struct {
+	From *time.Time `json:"from"` // first episode, null if not yet known
+	To   *time.Time `json:"to"`   // last episode, null while airing
+}
+
+// Broadcast describes the weekly broadcast slot of an airing anime
+type Broadcast struct {
+	Day      string `json:"day"`      // e.g. "Saturdays"
+	Time     string `json:"time"`     // e.g. "23:00"
+	Timezone string `json:"timezone"` // e.g. "Asia/Tokyo"
+	String   string `json:"string"`   // e.g. "Saturdays at 23:00 (JST)"
+}
+
 // AnimeStatistics contains detailed viewing statistics
 type AnimeStatistics struct {
 	Watching    int `json:"watching"`
@@ -239,6 +264,26 @@ func (c *Client) GetRecentRecommendations(ctx context.Context) (int, error) {
 	return result.Pagination.Items.Total, nil
 }
 
+// CurrentSeason returns the anime season for the given time (e.g., "Winter 2025")
+func CurrentSeason(now time.Time) string {
+	month := now.Month()
+	year := now.Year()
+
+	var season string
+	switch {
+	case month >= 1 && month <= 3:
+		season = "Winter"
+	case month >= 4 && month <= 6:
+		season = "Spring"
+	case month >= 7 && month <= 9:
+		season = "Summer"
+	default:
+		season = "Fall"
+	}
+
+	return fmt.Sprintf("%s %d", season, year)
+}
+
 // ActivityMetrics represents aggregated activity metrics
 type ActivityMetrics struct {
 	TotalActiveUsers    int
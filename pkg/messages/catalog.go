@@ -0,0 +1,40 @@
+package messages
+
+import (
+	weebcastv1alpha1 "github.com/weebcast/weebcast-operator/api/v1alpha1"
+)
+
+// DefaultLocale is the locale of WeebcastStatus. Its catalog is also the
+// fallback for levels missing from other catalogs.
+const DefaultLocale = "en"
+
+// titlePrefix prefixes messages with the anime title when there is one
+const titlePrefix = "{{with .Title}}[{{.}}] {{end}}"
+
+// builtinCatalogs are the default messages for each level by locale
+var builtinCatalogs = map[string]map[weebcastv1alpha1.ActivityLevel]string{
+	"en": {
+		weebcastv1alpha1.ActivityLevelCritical: titlePrefix + "🌀 TYPHOON ALERT! A category 5 weeb storm is making landfall! Extreme anime energy detected across all sectors. This is not a drill - expect maximum hype levels, server strain, and spontaneous waifu debates. All weebs advised to secure their watchlists!",
+		weebcastv1alpha1.ActivityLevelHigh:     titlePrefix + "⛈️ STORM WARNING! A massive weeb front is moving in! Heavy anime discussions expected with a high chance of trending hashtags. Take shelter in your favorite streaming site - it's going to be a wild one!",
+		weebcastv1alpha1.ActivityLevelMedium:   titlePrefix + "⛅ Partly cloudy conditions in the weeb-o-sphere. Moderate anime activity detected with occasional bursts of excitement. Good conditions for casual binge-watching. Keep an umbrella ready for surprise episode drops!",
+		weebcastv1alpha1.ActivityLevelLow:      titlePrefix + "☀️ Clear skies across the anime landscape! A peaceful day in the weeb-o-sphere. Perfect weather for catching up on your backlog or discovering hidden gems. Enjoy the calm before the next seasonal storm!",
+	},
+	"ja": {
+		weebcastv1alpha1.ActivityLevelCritical: titlePrefix + "🌀 台風警報！カテゴリー5のオタク嵐が上陸中！全域で極度のアニメエネルギーを観測しています。これは訓練ではありません。最大級の盛り上がり、サーバーの負荷、突発的な推し論争が予想されます。オタクの皆さんはウォッチリストを確保してください！",
+		weebcastv1alpha1.ActivityLevelHigh:     titlePrefix + "⛈️ 嵐警報！巨大なオタク前線が接近中！アニメの話題が激しく降り注ぎ、トレンド入りの可能性も高いでしょう。お気に入りの配信サイトに避難してください。荒れ模様になりそうです！",
+		weebcastv1alpha1.ActivityLevelMedium:   titlePrefix + "⛅ オタク圏は晴れ時々くもり。適度なアニメ活動を観測、ときどき興奮のにわか雨があるでしょう。気軽な一気見に最適なコンディションです。突然の新エピソード配信に備えて傘をお忘れなく！",
+		weebcastv1alpha1.ActivityLevelLow:      titlePrefix + "☀️ アニメ界は快晴！オタク圏は穏やかな一日です。積みアニメの消化や隠れた名作の発掘にぴったりの天気。次のシーズンの嵐の前の静けさをお楽しみください！",
+	},
+	"es": {
+		weebcastv1alpha1.ActivityLevelCritical: titlePrefix + "🌀 ¡ALERTA DE TIFÓN! ¡Una tormenta otaku de categoría 5 está tocando tierra! Se detecta energía anime extrema en todos los sectores. Esto no es un simulacro: se esperan niveles máximos de hype, servidores al límite y debates espontáneos sobre waifus. ¡Se aconseja a todos los otakus asegurar sus listas!",
+		weebcastv1alpha1.ActivityLevelHigh:     titlePrefix + "⛈️ ¡AVISO DE TORMENTA! ¡Se acerca un frente otaku masivo! Se esperan intensas discusiones de anime con alta probabilidad de hashtags en tendencia. Refúgiate en tu plataforma de streaming favorita: ¡va a ser una locura!",
+		weebcastv1alpha1.ActivityLevelMedium:   titlePrefix + "⛅ Parcialmente nublado en la otakusfera. Actividad anime moderada con ráfagas ocasionales de emoción. Buenas condiciones para maratones casuales. ¡Ten un paraguas a mano por si caen episodios sorpresa!",
+		weebcastv1alpha1.ActivityLevelLow:      titlePrefix + "☀️ ¡Cielos despejados en todo el paisaje anime! Un día tranquilo en la otakusfera. Clima perfecto para ponerte al día con tu lista pendiente o descubrir joyas ocultas. ¡Disfruta la calma antes de la próxima tormenta de temporada!",
+	},
+	"pt": {
+		weebcastv1alpha1.ActivityLevelCritical: titlePrefix + "🌀 ALERTA DE TUFÃO! Uma tempestade otaku de categoria 5 está chegando à costa! Energia anime extrema detectada em todos os setores. Isto não é um treinamento - espere níveis máximos de hype, servidores sobrecarregados e debates espontâneos sobre waifus. Todos os otakus devem proteger suas listas!",
+		weebcastv1alpha1.ActivityLevelHigh:     titlePrefix + "⛈️ ALERTA DE TEMPESTADE! Uma enorme frente otaku está se aproximando! Discussões intensas de anime são esperadas, com alta chance de hashtags em alta. Abrigue-se no seu site de streaming favorito - vai ser uma loucura!",
+		weebcastv1alpha1.ActivityLevelMedium:   titlePrefix + "⛅ Parcialmente nublado na otakusfera. Atividade anime moderada com rajadas ocasionais de empolgação. Boas condições para maratonas casuais. Mantenha o guarda-chuva por perto para episódios surpresa!",
+		weebcastv1alpha1.ActivityLevelLow:      titlePrefix + "☀️ Céu limpo em todo o cenário anime! Um dia tranquilo na otakusfera. Clima perfeito para colocar sua lista em dia ou descobrir joias escondidas. Aproveite a calmaria antes da próxima tempestade da temporada!",
+	},
+}
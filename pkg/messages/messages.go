@@ -0,0 +1,243 @@
+// Package messages renders the weather forecast messages published as a
+// monitor's WeebcastStatus.
+//
+// Messages are Go text/template templates, one per activity level and
+// locale, executed against Data. Built-in catalogs cover English, Japanese,
+// Spanish and Portuguese. Custom templates, e.g. from a ConfigMap, replace
+// built-in messages or add locales. Keys are a level for the default locale,
+// or <locale>.<level> for any other:
+//
+//	High: "{{with .Title}}[{{.}}] {{end}}⛈️ {{.Metrics.WatchingCount}} weebs watching!"
+//	fr.High: "{{with .Title}}[{{.}}] {{end}}⛈️ Alerte tempête !"
+//
+// Templates are validated when parsed by executing them against sample data,
+// so a typo in a field name is caught on load rather than on the next poll.
+package messages
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	weebcastv1alpha1 "github.com/weebcast/weebcast-operator/api/v1alpha1"
+)
+
+// Data is the input available to message templates
+type Data struct {
+	// Title is the anime title, empty for the overall MAL monitor
+	Title string
+
+	// Level is the built-in activity level (Low, Medium, High or Critical)
+	Level string
+
+	// CustomLevel and CustomLevelIcon are set when the monitor has a level ladder
+	CustomLevel     string
+	CustomLevelIcon string
+
+	// ActivityScore is the score the level was derived from
+	ActivityScore int
+
+	// Metrics are the latest fetched metrics
+	Metrics weebcastv1alpha1.AnimeActivityMetrics
+
+	// Delta is the change of each metric since the previous poll, and
+	// Elapsed the time between the polls. Both are zero on the first poll.
+	Delta   weebcastv1alpha1.AnimeActivityMetrics
+	Elapsed time.Duration
+
+	// Season is the current anime season, e.g. "Winter 2025"
+	Season string
+}
+
+// localePattern matches locale codes such as ja or pt-BR
+var localePattern = regexp.MustCompile(`^[a-z]{2,3}(-[A-Za-z0-9]{2,8})*$`)
+
+// funcs are the helper functions available to templates
+var funcs = template.FuncMap{
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	// signed formats a delta with an explicit sign, e.g. +42
+	"signed": func(v interface{}) string {
+		switch n := v.(type) {
+		case int:
+			return fmt.Sprintf("%+d", n)
+		case float64:
+			return fmt.Sprintf("%+.2f", n)
+		default:
+			return fmt.Sprint(v)
+		}
+	},
+}
+
+// Templates holds a message template per locale and activity level
+type Templates struct {
+	locales map[string]map[weebcastv1alpha1.ActivityLevel]*template.Template
+}
+
+// builtin is parsed once from builtinCatalogs
+var builtin = mustParseBuiltin()
+
+// Builtin returns the built-in messages
+func Builtin() *Templates {
+	return builtin
+}
+
+// mustParseBuiltin parses the built-in catalogs, panicking on a mistake in them
+func mustParseBuiltin() *Templates {
+	t := &Templates{locales: make(map[string]map[weebcastv1alpha1.ActivityLevel]*template.Template, len(builtinCatalogs))}
+	for locale, catalog := range builtinCatalogs {
+		t.locales[locale] = make(map[weebcastv1alpha1.ActivityLevel]*template.Template, len(catalog))
+		for level, source := range catalog {
+			t.locales[locale][level] = template.Must(newTemplate(locale + "." + string(level)).Parse(source))
+		}
+	}
+	return t
+}
+
+// Parse parses custom message templates keyed by level for the default
+// locale or <locale>.<level> for others. Messages without a template keep
+// the built-in message, and levels missing from a new locale use the default
+// locale's. Every template is executed against sample data, so Parse also
+// rejects templates that reference unknown fields.
+func Parse(sources map[string]string) (*Templates, error) {
+	t := &Templates{locales: make(map[string]map[weebcastv1alpha1.ActivityLevel]*template.Template, len(builtin.locales))}
+	for locale, levels := range builtin.locales {
+		t.locales[locale] = make(map[weebcastv1alpha1.ActivityLevel]*template.Template, len(levels))
+		for level, tmpl := range levels {
+			t.locales[locale][level] = tmpl
+		}
+	}
+
+	// Parse in a stable order so the first error reported is deterministic
+	keys := make([]string, 0, len(sources))
+	for key := range sources {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		locale, name, ok := strings.Cut(key, ".")
+		if !ok {
+			locale, name = DefaultLocale, key
+		}
+		if !localePattern.MatchString(locale) {
+			return nil, fmt.Errorf("invalid locale %q in key %q", locale, key)
+		}
+		level := weebcastv1alpha1.ActivityLevel(name)
+		if _, ok := builtinCatalogs[DefaultLocale][level]; !ok {
+			return nil, fmt.Errorf("unknown level %q in key %q, expected Low, Medium, High or Critical", name, key)
+		}
+
+		tmpl, err := newTemplate(locale + "." + name).Parse(sources[key])
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s template: %w", key, err)
+		}
+		if err := tmpl.Execute(&strings.Builder{}, sampleData(level)); err != nil {
+			return nil, fmt.Errorf("validating %s template: %w", key, err)
+		}
+
+		if t.locales[locale] == nil {
+			t.locales[locale] = make(map[weebcastv1alpha1.ActivityLevel]*template.Template)
+		}
+		t.locales[locale][level] = tmpl
+	}
+	return t, nil
+}
+
+// Locales returns the locales with a catalog, sorted
+func (t *Templates) Locales() []string {
+	if t == nil {
+		t = builtin
+	}
+
+	locales := make([]string, 0, len(t.locales))
+	for locale := range t.locales {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// Render renders the message in the default locale
+func (t *Templates) Render(data Data) (string, error) {
+	return t.RenderLocale(DefaultLocale, data)
+}
+
+// RenderLocale executes the template for data.Level in a locale. Levels
+// missing from the locale use the default locale's template, and an unknown
+// level uses the Low template.
+func (t *Templates) RenderLocale(locale string, data Data) (string, error) {
+	if t == nil {
+		t = builtin
+	}
+
+	level := weebcastv1alpha1.ActivityLevel(data.Level)
+	if _, ok := builtinCatalogs[DefaultLocale][level]; !ok {
+		level = weebcastv1alpha1.ActivityLevelLow
+	}
+	tmpl, ok := t.locales[locale][level]
+	if !ok {
+		tmpl = t.locales[DefaultLocale][level]
+	}
+
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("rendering %s message: %w", tmpl.Name(), err)
+	}
+	return sb.String(), nil
+}
+
+// RenderAll renders the message in every locale, keyed by locale
+func (t *Templates) RenderAll(data Data) (map[string]string, error) {
+	locales := t.Locales()
+	rendered := make(map[string]string, len(locales))
+	for _, locale := range locales {
+		message, err := t.RenderLocale(locale, data)
+		if err != nil {
+			return nil, err
+		}
+		rendered[locale] = message
+	}
+	return rendered, nil
+}
+
+// newTemplate creates an empty template with the helper functions
+func newTemplate(name string) *template.Template {
+	return template.New(name).Funcs(funcs).Option("missingkey=error")
+}
+
+// sampleData returns realistic data for validating templates
+func sampleData(level weebcastv1alpha1.ActivityLevel) Data {
+	return Data{
+		Title:           "Sousou no Frieren",
+		Level:           string(level),
+		CustomLevel:     "Gale",
+		CustomLevelIcon: "🌬️",
+		ActivityScore:   1500,
+		Metrics: weebcastv1alpha1.AnimeActivityMetrics{
+			ActiveUsers:      120000,
+			WatchingCount:    100000,
+			CompletedCount:   200000,
+			DroppedCount:     5000,
+			PlanToWatchCount: 300000,
+			Score:            9.1,
+			ScoredByCount:    400000,
+			Rank:             1,
+			Popularity:       150,
+			Members:          900000,
+			Favorites:        40000,
+		},
+		Delta: weebcastv1alpha1.AnimeActivityMetrics{
+			ActiveUsers:   500,
+			WatchingCount: 400,
+			Score:         0.01,
+			Members:       1200,
+			Favorites:     30,
+		},
+		Elapsed: 5 * time.Minute,
+		Season:  "Fall 2025",
+	}
+}
@@ -0,0 +1,91 @@
+package messages
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		sources map[string]string
+		wantErr string
+	}{
+		{name: "default locale", sources: map[string]string{"High": "⛈️ {{.Metrics.WatchingCount}} watching"}},
+		{name: "other locale", sources: map[string]string{"fr.High": "⛈️ Alerte tempête !"}},
+		{name: "invalid locale", sources: map[string]string{"FR.High": "x"}, wantErr: `invalid locale "FR"`},
+		{name: "unknown level", sources: map[string]string{"Severe": "x"}, wantErr: `unknown level "Severe"`},
+		{name: "syntax error", sources: map[string]string{"Low": "{{.Title"}, wantErr: "parsing Low template"},
+		{name: "unknown field", sources: map[string]string{"Low": "{{.Viewers}}"}, wantErr: "validating Low template"},
+		{name: "unknown function", sources: map[string]string{"Low": "{{title .Title}}"}, wantErr: "parsing Low template"},
+		{
+			name:    "first error in key order",
+			sources: map[string]string{"Medium": "{{.Nope}}", "High": "{{.Nope}}"},
+			wantErr: "validating High template",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.sources)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Parse() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Parse() error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRenderFallback(t *testing.T) {
+	templates, err := Parse(map[string]string{
+		"High":    "{{.Title}} custom high",
+		"fr.High": "{{.Title}} alerte",
+	})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	builtinLow, err := Builtin().RenderLocale("ja", Data{Title: "Frieren", Level: "Low"})
+	if err != nil {
+		t.Fatalf("RenderLocale() error = %v", err)
+	}
+	builtinMediumEn, err := Builtin().Render(Data{Title: "Frieren", Level: "Medium"})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		locale string
+		level  string
+		want   string
+	}{
+		{name: "custom template", locale: "en", level: "High", want: "Frieren custom high"},
+		{name: "new locale", locale: "fr", level: "High", want: "Frieren alerte"},
+		{name: "built-in message kept", locale: "ja", level: "Low", want: builtinLow},
+		{name: "level missing from new locale", locale: "fr", level: "Medium", want: builtinMediumEn},
+		{name: "unknown locale", locale: "de", level: "High", want: "Frieren custom high"},
+		{name: "unknown level", locale: "ja", level: "Severe", want: builtinLow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := templates.RenderLocale(tt.locale, Data{Title: "Frieren", Level: tt.level})
+			if err != nil {
+				t.Fatalf("RenderLocale() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("RenderLocale(%s, %s) = %q, want %q", tt.locale, tt.level, got, tt.want)
+			}
+		})
+	}
+
+	if locales := strings.Join(templates.Locales(), ","); locales != "en,es,fr,ja,pt" {
+		t.Errorf("Locales() = %s, want en,es,fr,ja,pt", locales)
+	}
+}
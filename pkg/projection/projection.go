@@ -0,0 +1,246 @@
+// Package projection projects a time series forward with Holt's linear trend
+// method, or the additive Holt-Winters method when the series has a
+// seasonal period, such as a weekly episode release.
+//
+// Samples are averaged into fixed-size steps first, so irregular polls and
+// downsampled history can be mixed. Smoothing parameters are picked by a
+// small grid search minimising the one-step-ahead error, and the confidence
+// band widens with the square root of the distance ahead.
+package projection
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// Method names the model a projection was made with
+type Method string
+
+const (
+	MethodHolt        Method = "Holt"
+	MethodHoltWinters Method = "HoltWinters"
+)
+
+// ErrInsufficientData is returned when there are too few steps to fit a model
+var ErrInsufficientData = errors.New("not enough data to project")
+
+// MinSteps is the fewest resampled steps Holt's method is fitted on
+const MinSteps = 6
+
+// defaultZ is the band width in standard deviations, for a ~95% band
+const defaultZ = 1.96
+
+// Point is a sample of the series
+type Point struct {
+	Time  time.Time
+	Value float64
+}
+
+// Projection is the projected value for the step starting at Time, with the
+// lower and upper bounds of its confidence band
+type Projection struct {
+	Time  time.Time
+	Value float64
+	Lower float64
+	Upper float64
+}
+
+// Options configures a projection
+type Options struct {
+	// Step is the length of each step the series is resampled into
+	Step time.Duration
+
+	// Horizon is the number of steps to project
+	Horizon int
+
+	// Season is the seasonal period in steps, or 0 for none. The seasonal
+	// model needs two full seasons of data and falls back to Holt's method
+	// without them.
+	Season int
+
+	// Z is the confidence band width in standard deviations (default 1.96)
+	Z float64
+}
+
+// Project fits a model to points, which must be in time order, and projects
+// it Horizon steps past the last one
+func Project(points []Point, opts Options) (Method, []Projection, error) {
+	if opts.Step <= 0 || opts.Horizon <= 0 {
+		return "", nil, errors.New("step and horizon must be positive")
+	}
+	if len(points) == 0 {
+		return "", nil, ErrInsufficientData
+	}
+
+	start := points[0].Time.Truncate(opts.Step)
+	series := Resample(points, start, opts.Step)
+	if len(series) < MinSteps {
+		return "", nil, ErrInsufficientData
+	}
+
+	method := MethodHolt
+	fit := fitHolt
+	if opts.Season > 1 && len(series) >= 2*opts.Season {
+		method = MethodHoltWinters
+		fit = func(y []float64, horizon int) ([]float64, float64) {
+			return fitHoltWinters(y, opts.Season, horizon)
+		}
+	}
+
+	forecast, sigma := fit(series, opts.Horizon)
+
+	z := opts.Z
+	if z <= 0 {
+		z = defaultZ
+	}
+	last := start.Add(time.Duration(len(series)-1) * opts.Step)
+
+	projections := make([]Projection, 0, opts.Horizon)
+	for h, value := range forecast {
+		band := z * sigma * math.Sqrt(float64(h+1))
+		projections = append(projections, Projection{
+			Time:  last.Add(time.Duration(h+1) * opts.Step),
+			Value: value,
+			Lower: value - band,
+			Upper: value + band,
+		})
+	}
+	return method, projections, nil
+}
+
+// Resample averages points into consecutive steps from start. Steps without
+// samples are interpolated linearly between their neighbours.
+func Resample(points []Point, start time.Time, step time.Duration) []float64 {
+	if len(points) == 0 {
+		return nil
+	}
+
+	n := int(points[len(points)-1].Time.Sub(start)/step) + 1
+	sums := make([]float64, n)
+	counts := make([]int, n)
+	for _, p := range points {
+		i := int(p.Time.Sub(start) / step)
+		if i < 0 || i >= n {
+			continue
+		}
+		sums[i] += p.Value
+		counts[i]++
+	}
+
+	series := make([]float64, n)
+	previous := -1
+	for i := range series {
+		if counts[i] == 0 {
+			continue
+		}
+		series[i] = sums[i] / float64(counts[i])
+
+		// Fill the gap since the previous step with samples
+		if previous >= 0 && i-previous > 1 {
+			for j := previous + 1; j < i; j++ {
+				frac := float64(j-previous) / float64(i-previous)
+				series[j] = series[previous] + frac*(series[i]-series[previous])
+			}
+		}
+		previous = i
+	}
+	return series
+}
+
+// Smoothing parameter grids searched when fitting
+var (
+	alphas = []float64{0.1, 0.2, 0.4, 0.6, 0.8}
+	betas  = []float64{0.01, 0.05, 0.1, 0.2, 0.4}
+	gammas = []float64{0.05, 0.1, 0.3, 0.5}
+)
+
+// fitHolt fits Holt's linear trend method and returns the forecast for the
+// next horizon steps and the standard deviation of one-step-ahead errors
+func fitHolt(y []float64, horizon int) ([]float64, float64) {
+	bestSSE := math.Inf(1)
+	var bestLevel, bestTrend float64
+
+	for _, alpha := range alphas {
+		for _, beta := range betas {
+			level, trend := y[0], y[1]-y[0]
+			sse := 0.0
+			for _, value := range y[1:] {
+				err := value - (level + trend)
+				sse += err * err
+
+				next := alpha*value + (1-alpha)*(level+trend)
+				trend = beta*(next-level) + (1-beta)*trend
+				level = next
+			}
+			if sse < bestSSE {
+				bestSSE, bestLevel, bestTrend = sse, level, trend
+			}
+		}
+	}
+
+	forecast := make([]float64, horizon)
+	for h := range forecast {
+		forecast[h] = bestLevel + float64(h+1)*bestTrend
+	}
+	return forecast, math.Sqrt(bestSSE / float64(len(y)-1))
+}
+
+// fitHoltWinters fits the additive Holt-Winters method with the given
+// seasonal period and returns the forecast for the next horizon steps and
+// the standard deviation of one-step-ahead errors
+func fitHoltWinters(y []float64, period, horizon int) ([]float64, float64) {
+	// Initialise from the first two seasons
+	first, second := mean(y[:period]), mean(y[period:2*period])
+	initialTrend := (second - first) / float64(period)
+
+	bestSSE := math.Inf(1)
+	var (
+		bestLevel, bestTrend float64
+		bestSeason           []float64
+	)
+
+	for _, alpha := range alphas {
+		for _, beta := range betas {
+			for _, gamma := range gammas {
+				// Seasonal offsets from the first season, detrended around its middle
+				season := make([]float64, len(y))
+				for i := 0; i < period; i++ {
+					season[i] = y[i] - (first + (float64(i)-float64(period-1)/2)*initialTrend)
+				}
+
+				// The level starts at the end of the first season
+				level, trend := first+float64(period-1)/2*initialTrend, initialTrend
+				sse := 0.0
+				for t := period; t < len(y); t++ {
+					err := y[t] - (level + trend + season[t-period])
+					sse += err * err
+
+					next := alpha*(y[t]-season[t-period]) + (1-alpha)*(level+trend)
+					trend = beta*(next-level) + (1-beta)*trend
+					season[t] = gamma*(y[t]-next) + (1-gamma)*season[t-period]
+					level = next
+				}
+				if sse < bestSSE {
+					bestSSE, bestLevel, bestTrend, bestSeason = sse, level, trend, season
+				}
+			}
+		}
+	}
+
+	n := len(y)
+	forecast := make([]float64, horizon)
+	for h := range forecast {
+		forecast[h] = bestLevel + float64(h+1)*bestTrend + bestSeason[n-period+h%period]
+	}
+	return forecast, math.Sqrt(bestSSE / float64(n-period))
+}
+
+// mean returns the arithmetic mean of values
+func mean(values []float64) float64 {
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
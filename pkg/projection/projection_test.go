@@ -0,0 +1,237 @@
+package projection
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+var start = time.Date(2026, 10, 5, 0, 0, 0, 0, time.UTC)
+
+// series returns hourly points with values f(0), f(1), ...
+func series(n int, f func(t int) float64) []Point {
+	points := make([]Point, n)
+	for t := range points {
+		points[t] = Point{Time: start.Add(time.Duration(t) * time.Hour), Value: f(t)}
+	}
+	return points
+}
+
+func TestResample(t *testing.T) {
+	at := func(d time.Duration, v float64) Point { return Point{Time: start.Add(d), Value: v} }
+
+	tests := []struct {
+		name   string
+		points []Point
+		want   []float64
+	}{
+		{
+			name:   "one point per step",
+			points: []Point{at(0, 1), at(time.Hour, 2), at(2*time.Hour, 3)},
+			want:   []float64{1, 2, 3},
+		},
+		{
+			name:   "points in a step are averaged",
+			points: []Point{at(0, 10), at(20*time.Minute, 20), at(40*time.Minute, 30), at(time.Hour, 5)},
+			want:   []float64{20, 5},
+		},
+		{
+			name:   "gaps are interpolated",
+			points: []Point{at(0, 10), at(3*time.Hour, 40), at(5*time.Hour, 0)},
+			want:   []float64{10, 20, 30, 40, 20, 0},
+		},
+		{
+			name:   "points before start are dropped",
+			points: []Point{at(-time.Hour, 100), at(0, 1), at(time.Hour, 2)},
+			want:   []float64{1, 2},
+		},
+		{
+			name: "no points",
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Resample(tt.points, start, time.Hour)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Resample() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if math.Abs(got[i]-tt.want[i]) > 1e-9 {
+					t.Fatalf("Resample() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestProject(t *testing.T) {
+	weekly := func(t int) float64 { return 100 * math.Sin(2*math.Pi*float64(t)/24) }
+
+	tests := []struct {
+		name      string
+		points    []Point
+		opts      Options
+		want      func(t int) float64
+		tolerance float64
+		method    Method
+	}{
+		{
+			name:      "linear series extrapolates the slope",
+			points:    series(48, func(t int) float64 { return 100 + 5*float64(t) }),
+			opts:      Options{Step: time.Hour, Horizon: 12},
+			want:      func(t int) float64 { return 100 + 5*float64(t) },
+			tolerance: 1e-6,
+			method:    MethodHolt,
+		},
+		{
+			name:      "periodic series reproduces the cycle",
+			points:    series(96, func(t int) float64 { return 500 + weekly(t) }),
+			opts:      Options{Step: time.Hour, Horizon: 48, Season: 24},
+			want:      func(t int) float64 { return 500 + weekly(t) },
+			tolerance: 5,
+			method:    MethodHoltWinters,
+		},
+		{
+			name:      "periodic series with a trend",
+			points:    series(96, func(t int) float64 { return 500 + 2*float64(t) + weekly(t) }),
+			opts:      Options{Step: time.Hour, Horizon: 36, Season: 24},
+			want:      func(t int) float64 { return 500 + 2*float64(t) + weekly(t) },
+			tolerance: 10,
+			method:    MethodHoltWinters,
+		},
+		{
+			name:      "short seasonal series falls back to Holt",
+			points:    series(30, func(t int) float64 { return 50 + float64(t) }),
+			opts:      Options{Step: time.Hour, Horizon: 6, Season: 24},
+			want:      func(t int) float64 { return 50 + float64(t) },
+			tolerance: 1e-6,
+			method:    MethodHolt,
+		},
+		{
+			name: "gap-filled series",
+			points: func() []Point {
+				points := series(24, func(t int) float64 { return 10 * float64(t) })
+				// Drop every other sample after the first few
+				var sparse []Point
+				for i, p := range points {
+					if i < 4 || i%2 == 1 {
+						sparse = append(sparse, p)
+					}
+				}
+				return sparse
+			}(),
+			opts:      Options{Step: time.Hour, Horizon: 4},
+			want:      func(t int) float64 { return 10 * float64(t) },
+			tolerance: 1e-6,
+			method:    MethodHolt,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			method, projections, err := Project(tt.points, tt.opts)
+			if err != nil {
+				t.Fatalf("Project() error = %v", err)
+			}
+			if method != tt.method {
+				t.Errorf("method = %s, want %s", method, tt.method)
+			}
+			if len(projections) != tt.opts.Horizon {
+				t.Fatalf("got %d projections, want %d", len(projections), tt.opts.Horizon)
+			}
+
+			last := tt.points[len(tt.points)-1].Time
+			for h, p := range projections {
+				wantTime := last.Add(time.Duration(h+1) * tt.opts.Step)
+				if !p.Time.Equal(wantTime) {
+					t.Errorf("projection %d at %s, want %s", h, p.Time, wantTime)
+				}
+				step := int(wantTime.Sub(start) / tt.opts.Step)
+				if want := tt.want(step); math.Abs(p.Value-want) > tt.tolerance {
+					t.Errorf("projection %d = %.2f, want %.2f ± %g", h, p.Value, want, tt.tolerance)
+				}
+				if p.Lower > p.Value || p.Upper < p.Value {
+					t.Errorf("projection %d = %.2f outside its band [%.2f, %.2f]", h, p.Value, p.Lower, p.Upper)
+				}
+			}
+		})
+	}
+}
+
+func TestProjectBandWidens(t *testing.T) {
+	// A noisy line, so the one-step-ahead errors are not zero
+	noise := []float64{3, -2, 4, -5, 1, -1, 2, -3}
+	points := series(48, func(t int) float64 { return 200 + 3*float64(t) + noise[t%len(noise)] })
+
+	_, projections, err := Project(points, Options{Step: time.Hour, Horizon: 9, Z: 2})
+	if err != nil {
+		t.Fatalf("Project() error = %v", err)
+	}
+
+	first := projections[0].Upper - projections[0].Value
+	if first <= 0 {
+		t.Fatalf("first band half-width = %g, want > 0", first)
+	}
+	for h, p := range projections {
+		upper, lower := p.Upper-p.Value, p.Value-p.Lower
+		want := first * math.Sqrt(float64(h+1))
+		if math.Abs(upper-want) > 1e-9 || math.Abs(lower-want) > 1e-9 {
+			t.Errorf("projection %d band = -%g/+%g, want ±%g", h, lower, upper, want)
+		}
+	}
+}
+
+func TestProjectInsufficientData(t *testing.T) {
+	linear := func(t int) float64 { return float64(t) }
+
+	tests := []struct {
+		name    string
+		points  []Point
+		opts    Options
+		wantErr error
+	}{
+		{
+			name:    "no points",
+			opts:    Options{Step: time.Hour, Horizon: 1},
+			wantErr: ErrInsufficientData,
+		},
+		{
+			name:    "one step short of MinSteps",
+			points:  series(MinSteps-1, linear),
+			opts:    Options{Step: time.Hour, Horizon: 1},
+			wantErr: ErrInsufficientData,
+		},
+		{
+			name:   "exactly MinSteps",
+			points: series(MinSteps, linear),
+			opts:   Options{Step: time.Hour, Horizon: 1},
+		},
+		{
+			name:    "MinSteps points within fewer steps",
+			points:  series(MinSteps, linear),
+			opts:    Options{Step: 2 * time.Hour, Horizon: 1},
+			wantErr: ErrInsufficientData,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := Project(tt.points, tt.opts)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Project() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestProjectInvalidOptions(t *testing.T) {
+	points := series(MinSteps, func(t int) float64 { return float64(t) })
+	for _, opts := range []Options{{Horizon: 1}, {Step: time.Hour}} {
+		if _, _, err := Project(points, opts); err == nil || errors.Is(err, ErrInsufficientData) {
+			t.Errorf("Project(%+v) error = %v, want an options error", opts, err)
+		}
+	}
+}
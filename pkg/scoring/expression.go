@@ -0,0 +1,168 @@
+// Package scoring evaluates user-defined activity score expressions written
+// in CEL (https://github.com/google/cel-spec).
+//
+// Expressions can use three variables:
+//
+//   - metrics: the latest fetched metrics, keyed by activeUsers, watching,
+//     completed, dropped, planToWatch, score, scoredBy, rank, popularity,
+//     members and favorites. All values are ints except score, a double.
+//   - delta: the change of each metric since the previous poll, with the
+//     same keys, plus hours (a double) for the time between polls.
+//   - history: the recent samples from status.history, oldest first, each
+//     with timestamp, members, watching, score, rank, activityScore and level.
+//
+// The expression must evaluate to a number, e.g.
+// metrics.watching * 5 + delta.members / 10.
+package scoring
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/google/cel-go/cel"
+)
+
+// costLimit bounds the work a single evaluation may do
+const costLimit = 1_000_000
+
+// MetricNames are the keys of the metrics and delta variables
+var MetricNames = []string{
+	"activeUsers", "watching", "completed", "dropped", "planToWatch",
+	"score", "scoredBy", "rank", "popularity", "members", "favorites",
+}
+
+// Input holds the variables available to an expression
+type Input struct {
+	Metrics map[string]interface{}
+	Delta   map[string]interface{}
+	History []map[string]interface{}
+}
+
+// Expression is a compiled activity score expression
+type Expression struct {
+	program cel.Program
+}
+
+var (
+	envOnce sync.Once
+	env     *cel.Env
+	envErr  error
+)
+
+// environment returns the shared CEL environment declaring the variables
+func environment() (*cel.Env, error) {
+	envOnce.Do(func() {
+		entry := cel.MapType(cel.StringType, cel.DynType)
+		env, envErr = cel.NewEnv(
+			cel.Variable("metrics", entry),
+			cel.Variable("delta", entry),
+			cel.Variable("history", cel.ListType(entry)),
+		)
+	})
+	return env, envErr
+}
+
+// Compile parses and type-checks an expression. Results are not cached;
+// callers evaluating the same expression repeatedly should keep it.
+func Compile(expr string) (*Expression, error) {
+	env, err := environment()
+	if err != nil {
+		return nil, fmt.Errorf("creating CEL environment: %w", err)
+	}
+
+	ast, issues := env.Compile(expr)
+	if issues != nil && issues.Err() != nil {
+		return nil, issues.Err()
+	}
+
+	out := ast.OutputType()
+	if !out.IsExactType(cel.IntType) && !out.IsExactType(cel.UintType) &&
+		!out.IsExactType(cel.DoubleType) && !out.IsExactType(cel.DynType) {
+		return nil, fmt.Errorf("expression must evaluate to a number, got %s", out)
+	}
+
+	program, err := env.Program(ast, cel.CostLimit(costLimit))
+	if err != nil {
+		return nil, fmt.Errorf("building program: %w", err)
+	}
+
+	return &Expression{program: program}, nil
+}
+
+// Validate compiles an expression and evaluates it once against sample
+// input, catching mistakes such as mixing ints and doubles that the type
+// checker cannot see through the dynamically typed metric maps
+func Validate(expr string) error {
+	expression, err := Compile(expr)
+	if err != nil {
+		return err
+	}
+	if _, err := expression.Eval(sampleInput()); err != nil {
+		return fmt.Errorf("evaluating against sample metrics: %w", err)
+	}
+	return nil
+}
+
+// Eval evaluates the expression and returns the activity score
+func (e *Expression) Eval(in Input) (int, error) {
+	history := make([]interface{}, 0, len(in.History))
+	for _, sample := range in.History {
+		history = append(history, sample)
+	}
+
+	out, _, err := e.program.Eval(map[string]interface{}{
+		"metrics": in.Metrics,
+		"delta":   in.Delta,
+		"history": history,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	switch v := out.Value().(type) {
+	case int64:
+		return int(v), nil
+	case uint64:
+		return int(v), nil
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return 0, fmt.Errorf("expression evaluated to %v", v)
+		}
+		return int(math.Round(v)), nil
+	default:
+		return 0, fmt.Errorf("expression must evaluate to a number, got %s", out.Type())
+	}
+}
+
+// sampleInput returns non-zero values for every variable so validation does
+// not trip over division by zero
+func sampleInput() Input {
+	metrics := make(map[string]interface{}, len(MetricNames))
+	delta := make(map[string]interface{}, len(MetricNames)+1)
+	for _, name := range MetricNames {
+		if name == "score" {
+			metrics[name] = 8.0
+			delta[name] = 0.1
+			continue
+		}
+		metrics[name] = int64(1000)
+		delta[name] = int64(10)
+	}
+	delta["hours"] = 1.0
+
+	return Input{
+		Metrics: metrics,
+		Delta:   delta,
+		History: []map[string]interface{}{{
+			"timestamp":     time.Unix(0, 0).UTC(),
+			"members":       int64(990),
+			"watching":      int64(990),
+			"score":         7.9,
+			"rank":          int64(100),
+			"activityScore": int64(500),
+			"level":         "Medium",
+		}},
+	}
+}
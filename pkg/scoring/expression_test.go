@@ -0,0 +1,156 @@
+package scoring
+
+import (
+	"strings"
+	"testing"
+)
+
+// input returns metrics and deltas where every int metric is value and
+// delta, with score and hours set separately
+func input(value, delta int64, score, hours float64) Input {
+	in := Input{
+		Metrics: make(map[string]interface{}, len(MetricNames)),
+		Delta:   make(map[string]interface{}, len(MetricNames)+1),
+	}
+	for _, name := range MetricNames {
+		in.Metrics[name] = value
+		in.Delta[name] = delta
+	}
+	in.Metrics["score"] = score
+	in.Delta["score"] = 0.0
+	in.Delta["hours"] = hours
+	return in
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    string
+		wantErr string
+	}{
+		{name: "syntax error", expr: "metrics.watching *", wantErr: "Syntax error"},
+		{name: "undeclared variable", expr: "stats.watching", wantErr: "undeclared reference"},
+		{name: "boolean result", expr: "metrics.watching > 10", wantErr: "must evaluate to a number"},
+		{name: "string result", expr: `"storm"`, wantErr: "must evaluate to a number"},
+		{name: "list result", expr: "history", wantErr: "must evaluate to a number"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Compile(tt.expr)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Compile(%q) error = %v, want one containing %q", tt.expr, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEval(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+		in   Input
+		want int
+	}{
+		{
+			name: "int arithmetic",
+			expr: "metrics.watching * 5 + metrics.favorites",
+			in:   input(100, 0, 8, 1),
+			want: 600,
+		},
+		{
+			name: "doubles are rounded",
+			expr: "metrics.score * 100.0 + 0.5",
+			in:   input(0, 0, 8.42, 1),
+			want: 843,
+		},
+		{
+			name: "delta per hour",
+			expr: "double(delta.members) / delta.hours",
+			in:   input(5000, 300, 8, 2),
+			want: 150,
+		},
+		{
+			name: "zero deltas on the first poll",
+			expr: "metrics.watching + delta.watching * 10",
+			in:   input(100, 0, 8, 0),
+			want: 100,
+		},
+		{
+			name: "history",
+			expr: "size(history) > 0 ? history[0].activityScore : 0",
+			in: func() Input {
+				in := input(0, 0, 8, 1)
+				in.History = []map[string]interface{}{{"activityScore": int64(42)}}
+				return in
+			}(),
+			want: 42,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expression, err := Compile(tt.expr)
+			if err != nil {
+				t.Fatalf("Compile(%q) error = %v", tt.expr, err)
+			}
+			got, err := expression.Eval(tt.in)
+			if err != nil {
+				t.Fatalf("Eval() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Eval() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvalRuntimeErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    string
+		wantErr string
+	}{
+		{name: "division by zero", expr: "metrics.watching / delta.watching", wantErr: "division by zero"},
+		{name: "mixed int and double", expr: "metrics.score + metrics.watching", wantErr: "no such overload"},
+		{name: "unknown metric", expr: "metrics.viewers", wantErr: "no such key"},
+		{name: "not a number", expr: "0.0 / double(delta.watching)", wantErr: "evaluated to NaN"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expression, err := Compile(tt.expr)
+			if err != nil {
+				t.Fatalf("Compile(%q) error = %v", tt.expr, err)
+			}
+			_, err = expression.Eval(input(100, 0, 8, 1))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Eval() error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    string
+		wantErr bool
+	}{
+		{name: "valid", expr: "metrics.watching * 5 + delta.members / 10"},
+		{name: "valid double", expr: "metrics.score * 100.0 + double(delta.members) / delta.hours"},
+		{name: "compile error", expr: "metrics.watching >", wantErr: true},
+		// Passes the type checker through the dynamic maps, fails on sample input
+		{name: "mixed int and double", expr: "metrics.watching * metrics.score", wantErr: true},
+		{name: "unknown metric", expr: "metrics.viewers * 2", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.expr)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate(%q) error = %v, wantErr %v", tt.expr, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -6,12 +6,17 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 )
 
+// DefaultCloudflareAPIURL is the base URL of the Cloudflare v4 API
+const DefaultCloudflareAPIURL = "https://api.cloudflare.com/client/v4"
+
 // CloudflareKVClient pushes activity data to Cloudflare Workers KV
 type CloudflareKVClient struct {
 	httpClient  *http.Client
+	baseURL     string
 	accountID   string
 	namespaceID string
 	apiToken    string
@@ -23,12 +28,20 @@ func NewCloudflareKVClient(accountID, namespaceID, apiToken string) *CloudflareK
 		httpClient: &http.Client{
 			Timeout: 10 * time.Second,
 		},
+		baseURL:     DefaultCloudflareAPIURL,
 		accountID:   accountID,
 		namespaceID: namespaceID,
 		apiToken:    apiToken,
 	}
 }
 
+// WithBaseURL points the client at a different Cloudflare API endpoint,
+// such as a local stand-in server
+func (c *CloudflareKVClient) WithBaseURL(baseURL string) *CloudflareKVClient {
+	c.baseURL = strings.TrimSuffix(baseURL, "/")
+	return c
+}
+
 // ActivityPayload is the data structure sent to Cloudflare
 type ActivityPayload struct {
 	MonitorName    string         `json:"monitorName"`
@@ -41,6 +54,71 @@ type ActivityPayload struct {
 	SeasonalAnime  []TrendingItem `json:"seasonalAnime,omitempty"`
 	CurrentSeason  string         `json:"currentSeason,omitempty"`
 	LastUpdated    time.Time      `json:"lastUpdated"`
+
+	// LastActivityChange is when the activity level last changed
+	LastActivityChange *time.Time `json:"lastActivityChange,omitempty"`
+
+	// Broadcast is the weekly broadcast slot of an airing anime
+	Broadcast *BroadcastPayload `json:"broadcast,omitempty"`
+
+	// Level is the monitor's level on its custom ladder. ActivityLevel still
+	// carries the built-in level the custom level maps to.
+	Level *LevelPayload `json:"level,omitempty"`
+
+	// LocalizedStatus is WeebcastStatus in every available locale, keyed by locale
+	LocalizedStatus map[string]string `json:"localizedStatus,omitempty"`
+
+	// TrendingUpdated and SeasonalUpdated are when each list was last
+	// fetched. A list older than LastUpdated is stale: its fetch failed and
+	// the previous list was kept.
+	TrendingUpdated *time.Time `json:"trendingUpdated,omitempty"`
+	SeasonalUpdated *time.Time `json:"seasonalUpdated,omitempty"`
+
+	// Forecast is the current activity as structured weather
+	Forecast *ForecastPayload `json:"forecast,omitempty"`
+
+	// Outlook is the projected activity for the coming time buckets
+	Outlook *OutlookPayload `json:"outlook,omitempty"`
+}
+
+// OutlookPayload is the projected activity for the coming time buckets
+type OutlookPayload struct {
+	Method      string          `json:"method"`
+	GeneratedAt time.Time       `json:"generatedAt"`
+	Buckets     []OutlookBucket `json:"buckets"`
+}
+
+// OutlookBucket is the projected activity for one time bucket
+type OutlookBucket struct {
+	Start time.Time `json:"start"`
+	Score int       `json:"score"`
+	Lower int       `json:"lower"`
+	Upper int       `json:"upper"`
+	Level string    `json:"level"`
+}
+
+// ForecastPayload describes the current activity as weather
+type ForecastPayload struct {
+	Condition           string `json:"condition"`
+	Icon                string `json:"icon"`
+	TemperatureCelsius  int    `json:"temperatureCelsius"`
+	WindSpeedKmh        int    `json:"windSpeedKmh"`
+	PrecipitationChance int    `json:"precipitationChance"`
+}
+
+// LevelPayload describes a level on a monitor's custom ladder
+type LevelPayload struct {
+	Name string `json:"name"`
+	Icon string `json:"icon,omitempty"`
+}
+
+// BroadcastPayload describes when new episodes are broadcast
+type BroadcastPayload struct {
+	Day       string     `json:"day"`
+	Time      string     `json:"time"`
+	Timezone  string     `json:"timezone"`
+	Episodes  int        `json:"episodes,omitempty"`
+	Premiered *time.Time `json:"premiered,omitempty"`
 }
 
 // MetricsPayload contains activity metrics
@@ -75,8 +153,8 @@ func (c *CloudflareKVClient) PushActivity(ctx context.Context, key string, paylo
 	}
 
 	url := fmt.Sprintf(
-		"https://api.cloudflare.com/client/v4/accounts/%s/storage/kv/namespaces/%s/values/%s",
-		c.accountID, c.namespaceID, key,
+		"%s/accounts/%s/storage/kv/namespaces/%s/values/%s",
+		c.baseURL, c.accountID, c.namespaceID, key,
 	)
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodPut, url, bytes.NewReader(data))
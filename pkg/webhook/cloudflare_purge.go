@@ -0,0 +1,200 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// maxPurgeFilesPerRequest is the number of URLs Cloudflare accepts per purge call
+const maxPurgeFilesPerRequest = 30
+
+// CloudflareCachePurger purges cached URLs from the Cloudflare CDN.
+// URLs are queued after each successful publish and flushed in batches,
+// so a burst of reconciles results in a handful of purge calls.
+type CloudflareCachePurger struct {
+	httpClient   *http.Client
+	baseURL      string
+	zoneID       string
+	apiToken     string
+	urlTemplates []string
+	interval     time.Duration
+
+	mu      sync.Mutex
+	pending map[string]struct{}
+}
+
+// NewCloudflareCachePurger creates a new cache purger for the given zone.
+// Each URL template may contain an {animeId} placeholder, in which case it is
+// only purged for monitors of a specific anime.
+func NewCloudflareCachePurger(zoneID, apiToken string, urlTemplates []string) *CloudflareCachePurger {
+	return &CloudflareCachePurger{
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		baseURL:      DefaultCloudflareAPIURL,
+		zoneID:       zoneID,
+		apiToken:     apiToken,
+		urlTemplates: urlTemplates,
+		interval:     10 * time.Second,
+		pending:      make(map[string]struct{}),
+	}
+}
+
+// WithBaseURL points the purger at a different Cloudflare API endpoint,
+// such as a local stand-in server
+func (c *CloudflareCachePurger) WithBaseURL(baseURL string) *CloudflareCachePurger {
+	c.baseURL = strings.TrimSuffix(baseURL, "/")
+	return c
+}
+
+// WithInterval sets how often queued URLs are flushed
+func (c *CloudflareCachePurger) WithInterval(interval time.Duration) *CloudflareCachePurger {
+	if interval > 0 {
+		c.interval = interval
+	}
+	return c
+}
+
+// Queue schedules the URLs affected by a published payload for purging
+func (c *CloudflareCachePurger) Queue(payload *ActivityPayload) {
+	urls := c.urlsFor(payload)
+	if len(urls) == 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, u := range urls {
+		c.pending[u] = struct{}{}
+	}
+}
+
+// urlsFor expands the configured URL templates for a payload
+func (c *CloudflareCachePurger) urlsFor(payload *ActivityPayload) []string {
+	urls := make([]string, 0, len(c.urlTemplates))
+	for _, tmpl := range c.urlTemplates {
+		if strings.Contains(tmpl, "{animeId}") {
+			if payload.AnimeID == 0 {
+				continue
+			}
+			tmpl = strings.ReplaceAll(tmpl, "{animeId}", strconv.Itoa(payload.AnimeID))
+		}
+		urls = append(urls, tmpl)
+	}
+	return urls
+}
+
+// Start flushes queued URLs every interval until the context is cancelled.
+// It implements manager.Runnable.
+func (c *CloudflareCachePurger) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("cloudflare-purge")
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			// Give the last batch a chance to go out before shutting down
+			flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			if err := c.Flush(flushCtx); err != nil {
+				logger.Error(err, "Failed to purge Cloudflare cache on shutdown")
+			}
+			cancel()
+			return nil
+		case <-ticker.C:
+			if err := c.Flush(ctx); err != nil {
+				logger.Error(err, "Failed to purge Cloudflare cache")
+			}
+		}
+	}
+}
+
+// Flush purges all queued URLs. URLs from failed batches are requeued.
+func (c *CloudflareCachePurger) Flush(ctx context.Context) error {
+	c.mu.Lock()
+	urls := make([]string, 0, len(c.pending))
+	for u := range c.pending {
+		urls = append(urls, u)
+	}
+	c.pending = make(map[string]struct{})
+	c.mu.Unlock()
+
+	sort.Strings(urls)
+
+	var firstErr error
+	for start := 0; start < len(urls); start += maxPurgeFilesPerRequest {
+		end := start + maxPurgeFilesPerRequest
+		if end > len(urls) {
+			end = len(urls)
+		}
+		batch := urls[start:end]
+
+		if err := c.purge(ctx, batch); err != nil {
+			c.mu.Lock()
+			for _, u := range batch {
+				c.pending[u] = struct{}{}
+			}
+			c.mu.Unlock()
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+
+	return firstErr
+}
+
+// purge sends a single purge_cache request for a batch of URLs
+func (c *CloudflareCachePurger) purge(ctx context.Context, urls []string) error {
+	data, err := json.Marshal(map[string][]string{"files": urls})
+	if err != nil {
+		return fmt.Errorf("marshaling purge request: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/zones/%s/purge_cache", c.baseURL, c.zoneID)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.apiToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("executing request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Success bool `json:"success"`
+		Errors  []struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil && resp.StatusCode == http.StatusOK {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK || !result.Success {
+		if len(result.Errors) > 0 {
+			return fmt.Errorf("purge failed with status %d: %s", resp.StatusCode, result.Errors[0].Message)
+		}
+		return fmt.Errorf("unexpected status: %d", resp.StatusCode)
+	}
+
+	return nil
+}
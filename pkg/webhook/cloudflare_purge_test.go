@@ -0,0 +1,216 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+)
+
+// purgeServer is a stand-in for the Cloudflare purge_cache endpoint that
+// records every batch it receives and fails the first failures requests
+type purgeServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	batches  [][]string
+	failures int
+}
+
+func newPurgeServer(t *testing.T, failures int) *purgeServer {
+	s := &purgeServer{failures: failures}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/zones/zone/purge_cache" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer token" {
+			t.Errorf("Authorization = %q, want Bearer token", got)
+		}
+
+		var body struct {
+			Files []string `json:"files"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decoding purge request: %v", err)
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.batches = append(s.batches, body.Files)
+		if s.failures > 0 {
+			s.failures--
+			w.WriteHeader(http.StatusTooManyRequests)
+			fmt.Fprint(w, `{"success":false,"errors":[{"code":10000,"message":"rate limited"}]}`)
+			return
+		}
+		fmt.Fprint(w, `{"success":true,"errors":[]}`)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// takeBatches returns and clears the batches received so far
+func (s *purgeServer) takeBatches() [][]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	batches := s.batches
+	s.batches = nil
+	return batches
+}
+
+// stubPublisher accepts every payload
+type stubPublisher struct{}
+
+func (stubPublisher) Name() string { return "stub" }
+
+func (stubPublisher) PushActivity(context.Context, string, *ActivityPayload) error { return nil }
+
+var purgeTemplates = []string{
+	"https://api.weebcast.com/api/activity",
+	"https://api.weebcast.com/api/anime/{animeId}",
+}
+
+// publishMonitors publishes a payload for each anime ID through the purger
+func publishMonitors(t *testing.T, purger *CloudflareCachePurger, animeIDs ...int) {
+	t.Helper()
+	publisher := purger.Wrap(stubPublisher{})
+	for _, id := range animeIDs {
+		payload := &ActivityPayload{MonitorName: fmt.Sprintf("monitor-%d", id), AnimeID: id}
+		if err := publisher.PushActivity(context.Background(), fmt.Sprintf("anime-%d", id), payload); err != nil {
+			t.Fatalf("PushActivity: %v", err)
+		}
+	}
+}
+
+func TestCachePurgerBatchesAcrossMonitors(t *testing.T) {
+	server := newPurgeServer(t, 0)
+	purger := NewCloudflareCachePurger("zone", "token", purgeTemplates).WithBaseURL(server.URL)
+
+	// Every monitor purges /api/activity, and each anime monitor its own URL
+	publishMonitors(t, purger, 0, 1, 2, 3)
+	if err := purger.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	batches := server.takeBatches()
+	if len(batches) != 1 {
+		t.Fatalf("got %d purge calls, want 1: %v", len(batches), batches)
+	}
+	want := []string{
+		"https://api.weebcast.com/api/activity",
+		"https://api.weebcast.com/api/anime/1",
+		"https://api.weebcast.com/api/anime/2",
+		"https://api.weebcast.com/api/anime/3",
+	}
+	if got := batches[0]; !equalStrings(got, want) {
+		t.Errorf("purged %v, want %v", got, want)
+	}
+}
+
+func TestCachePurgerSplitsLargeBatches(t *testing.T) {
+	server := newPurgeServer(t, 0)
+	purger := NewCloudflareCachePurger("zone", "token", purgeTemplates).WithBaseURL(server.URL)
+
+	ids := make([]int, 45)
+	for i := range ids {
+		ids[i] = i + 1
+	}
+	publishMonitors(t, purger, ids...)
+	if err := purger.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	batches := server.takeBatches()
+	if len(batches) != 2 {
+		t.Fatalf("got %d purge calls, want 2", len(batches))
+	}
+	seen := make(map[string]int)
+	for _, batch := range batches {
+		if len(batch) > maxPurgeFilesPerRequest {
+			t.Errorf("batch of %d URLs exceeds %d", len(batch), maxPurgeFilesPerRequest)
+		}
+		for _, u := range batch {
+			seen[u]++
+		}
+	}
+	// 45 anime URLs plus /api/activity, each purged once
+	if len(seen) != 46 {
+		t.Errorf("purged %d distinct URLs, want 46", len(seen))
+	}
+	for u, n := range seen {
+		if n != 1 {
+			t.Errorf("%s purged %d times", u, n)
+		}
+	}
+}
+
+func TestCachePurgerCollapsesDuplicates(t *testing.T) {
+	server := newPurgeServer(t, 0)
+	purger := NewCloudflareCachePurger("zone", "token", purgeTemplates).WithBaseURL(server.URL)
+
+	// The same monitor published repeatedly between flushes
+	publishMonitors(t, purger, 7, 7, 7, 0, 0)
+	if err := purger.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	batches := server.takeBatches()
+	want := []string{
+		"https://api.weebcast.com/api/activity",
+		"https://api.weebcast.com/api/anime/7",
+	}
+	if len(batches) != 1 || !equalStrings(batches[0], want) {
+		t.Errorf("purge calls = %v, want [%v]", batches, want)
+	}
+}
+
+func TestCachePurgerRequeuesFailedBatches(t *testing.T) {
+	server := newPurgeServer(t, 1)
+	purger := NewCloudflareCachePurger("zone", "token", purgeTemplates).WithBaseURL(server.URL)
+
+	publishMonitors(t, purger, 1)
+	if err := purger.Flush(context.Background()); err == nil {
+		t.Fatal("Flush succeeded, want the stand-in's rate limit error")
+	}
+	failed := server.takeBatches()
+	if len(failed) != 1 {
+		t.Fatalf("got %d purge calls, want 1", len(failed))
+	}
+
+	// The next flush retries the failed batch
+	if err := purger.Flush(context.Background()); err != nil {
+		t.Fatalf("retry Flush: %v", err)
+	}
+	retried := server.takeBatches()
+	if len(retried) != 1 || !equalStrings(retried[0], failed[0]) {
+		t.Errorf("retry purged %v, want %v", retried, failed)
+	}
+
+	// Nothing is left once the retry succeeds
+	if err := purger.Flush(context.Background()); err != nil {
+		t.Fatalf("final Flush: %v", err)
+	}
+	if extra := server.takeBatches(); len(extra) != 0 {
+		t.Errorf("final flush purged %v, want nothing", extra)
+	}
+}
+
+// equalStrings compares two string slices ignoring order
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a, b = append([]string(nil), a...), append([]string(nil), b...)
+	sort.Strings(a)
+	sort.Strings(b)
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,147 @@
+package webhook
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	weebcastv1alpha1 "github.com/weebcast/weebcast-operator/api/v1alpha1"
+)
+
+// OverallActivityKey is the storage key used for the overall MAL activity monitor
+const OverallActivityKey = "mal-overall"
+
+// ActivityKey returns the storage key for a monitor, matching the keys the
+// worker API reads (anime-<id>, mal-overall, or the monitor name)
+func ActivityKey(monitor *weebcastv1alpha1.AnimeMonitor) string {
+	return activityKey(monitor.Name, monitor.Spec.AnimeID)
+}
+
+// payloadKey returns the storage key a payload was published under
+func payloadKey(payload *ActivityPayload) string {
+	return activityKey(payload.MonitorName, payload.AnimeID)
+}
+
+func activityKey(name string, animeID int) string {
+	switch {
+	case animeID > 0:
+		return fmt.Sprintf("anime-%d", animeID)
+	case strings.Contains(name, "overall"):
+		return OverallActivityKey
+	default:
+		return name
+	}
+}
+
+// NewActivityPayload builds the published payload from a monitor's status
+func NewActivityPayload(monitor *weebcastv1alpha1.AnimeMonitor) *ActivityPayload {
+	status := monitor.Status
+
+	payload := &ActivityPayload{
+		MonitorName:     monitor.Name,
+		AnimeID:         monitor.Spec.AnimeID,
+		AnimeName:       monitor.Spec.AnimeName,
+		ActivityLevel:   string(status.ActivityLevel),
+		WeebcastStatus:  status.WeebcastStatus,
+		LocalizedStatus: status.LocalizedStatus,
+		Metrics: MetricsPayload{
+			ActiveUsers:   status.Metrics.ActiveUsers,
+			WatchingCount: status.Metrics.WatchingCount,
+			Members:       status.Metrics.Members,
+			Score:         status.Metrics.Score,
+			Rank:          status.Metrics.Rank,
+			Favorites:     status.Metrics.Favorites,
+		},
+		TrendingAnime: newTrendingItems(status.TrendingAnime),
+		SeasonalAnime: newTrendingItems(status.SeasonalAnime),
+		CurrentSeason: status.CurrentSeason,
+		LastUpdated:   status.LastChecked.Time,
+	}
+
+	if payload.LastUpdated.IsZero() {
+		payload.LastUpdated = time.Now()
+	}
+
+	if status.Broadcast != nil {
+		payload.Broadcast = &BroadcastPayload{
+			Day:      status.Broadcast.Day,
+			Time:     status.Broadcast.Time,
+			Timezone: status.Broadcast.Timezone,
+			Episodes: status.Broadcast.Episodes,
+		}
+		if status.Broadcast.Premiered != nil {
+			premiered := status.Broadcast.Premiered.Time
+			payload.Broadcast.Premiered = &premiered
+		}
+	}
+
+	if status.CustomLevel != "" {
+		payload.Level = &LevelPayload{
+			Name: status.CustomLevel,
+			Icon: status.CustomLevelIcon,
+		}
+	}
+
+	if status.Forecast != nil {
+		payload.Forecast = &ForecastPayload{
+			Condition:           string(status.Forecast.Condition),
+			Icon:                status.Forecast.Icon,
+			TemperatureCelsius:  status.Forecast.TemperatureCelsius,
+			WindSpeedKmh:        status.Forecast.WindSpeedKmh,
+			PrecipitationChance: status.Forecast.PrecipitationChance,
+		}
+	}
+
+	if status.Outlook != nil {
+		payload.Outlook = &OutlookPayload{
+			Method:      status.Outlook.Method,
+			GeneratedAt: status.Outlook.GeneratedAt.Time,
+			Buckets:     make([]OutlookBucket, 0, len(status.Outlook.Buckets)),
+		}
+		for _, bucket := range status.Outlook.Buckets {
+			payload.Outlook.Buckets = append(payload.Outlook.Buckets, OutlookBucket{
+				Start: bucket.Start.Time,
+				Score: bucket.Score,
+				Lower: bucket.Lower,
+				Upper: bucket.Upper,
+				Level: string(bucket.Level),
+			})
+		}
+	}
+
+	if !status.LastActivityChange.IsZero() {
+		changed := status.LastActivityChange.Time
+		payload.LastActivityChange = &changed
+	}
+
+	if status.TrendingAnimeUpdated != nil {
+		updated := status.TrendingAnimeUpdated.Time
+		payload.TrendingUpdated = &updated
+	}
+	if status.SeasonalAnimeUpdated != nil {
+		updated := status.SeasonalAnimeUpdated.Time
+		payload.SeasonalUpdated = &updated
+	}
+
+	return payload
+}
+
+// newTrendingItems converts status trending entries into payload items
+func newTrendingItems(entries []weebcastv1alpha1.TrendingAnime) []TrendingItem {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	items := make([]TrendingItem, 0, len(entries))
+	for _, entry := range entries {
+		items = append(items, TrendingItem{
+			ID:            entry.ID,
+			Title:         entry.Title,
+			Score:         entry.Score,
+			Members:       entry.Members,
+			ActivityLevel: string(entry.ActivityLevel),
+			ImageURL:      entry.ImageURL,
+		})
+	}
+	return items
+}
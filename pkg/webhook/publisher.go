@@ -0,0 +1,82 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+)
+
+// Publisher pushes activity payloads to a backing store read by weebcast.com
+type Publisher interface {
+	// Name identifies the publisher in logs
+	Name() string
+
+	// PushActivity stores the payload under the given key
+	PushActivity(ctx context.Context, key string, payload *ActivityPayload) error
+}
+
+// DocumentPublisher is implemented by publishers that can also host
+// arbitrary files next to the activity payloads, such as feeds and badges
+type DocumentPublisher interface {
+	Publisher
+
+	// PutDocument stores a file at the given path relative to the publisher root
+	PutDocument(ctx context.Context, path, contentType string, data []byte) error
+}
+
+// ErrDocumentNotFound is returned by GetDocument for paths never written
+var ErrDocumentNotFound = errors.New("document not found")
+
+// DocumentReader is implemented by document publishers that can read back
+// the files they host, so documents built up over time survive restarts
+type DocumentReader interface {
+	DocumentPublisher
+
+	// GetDocument returns the file at the given path relative to the
+	// publisher root, or ErrDocumentNotFound
+	GetDocument(ctx context.Context, path string) ([]byte, error)
+}
+
+// DocumentRemover is implemented by document publishers that can delete
+// the files they host, so documents of deleted monitors are not left behind
+type DocumentRemover interface {
+	DocumentPublisher
+
+	// DeleteDocument removes the file at the given path relative to the
+	// publisher root. Paths never written are not an error.
+	DeleteDocument(ctx context.Context, path string) error
+}
+
+// ActivityRemover is implemented by publishers that list every monitor, so
+// deleted monitors can be dropped from the listing
+type ActivityRemover interface {
+	Publisher
+
+	// RemoveActivity deletes the payload stored under the given key
+	RemoveActivity(ctx context.Context, key string) error
+}
+
+// Name identifies the Cloudflare KV publisher in logs
+func (c *CloudflareKVClient) Name() string {
+	return "cloudflare-kv"
+}
+
+// Wrap returns a publisher that queues a cache purge after every
+// successful publish through the given publisher
+func (c *CloudflareCachePurger) Wrap(publisher Publisher) Publisher {
+	return &purgingPublisher{Publisher: publisher, purger: c}
+}
+
+// purgingPublisher queues cache purges for payloads published successfully
+type purgingPublisher struct {
+	Publisher
+	purger *CloudflareCachePurger
+}
+
+// PushActivity publishes the payload and queues its URLs for purging
+func (p *purgingPublisher) PushActivity(ctx context.Context, key string, payload *ActivityPayload) error {
+	if err := p.Publisher.PushActivity(ctx, key, payload); err != nil {
+		return err
+	}
+	p.purger.Queue(payload)
+	return nil
+}
@@ -0,0 +1,290 @@
+package webhook
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RedisConfig configures the Redis publisher
+type RedisConfig struct {
+	// Addr is the host:port of the Redis server
+	Addr string
+	// Username and Password authenticate with AUTH when set
+	Username string
+	Password string
+	// DB selects the logical database
+	DB int
+	// KeyPrefix is prepended to every payload key, e.g. "weebcast:"
+	KeyPrefix string
+	// Channel receives a message whenever a monitor's activity level changes
+	Channel string
+}
+
+// ActivityChangeMessage is published on the Redis channel when a level changes
+type ActivityChangeMessage struct {
+	Key            string    `json:"key"`
+	MonitorName    string    `json:"monitorName"`
+	AnimeID        int       `json:"animeId,omitempty"`
+	AnimeName      string    `json:"animeName,omitempty"`
+	PreviousLevel  string    `json:"previousLevel,omitempty"`
+	ActivityLevel  string    `json:"activityLevel"`
+	WeebcastStatus string    `json:"weebcastStatus"`
+	ChangedAt      time.Time `json:"changedAt"`
+}
+
+// RedisClient writes each monitor's payload to a Redis key and publishes an
+// ActivityChangeMessage whenever the stored activity level changes, so
+// consumers can both poll and subscribe
+type RedisClient struct {
+	config  RedisConfig
+	timeout time.Duration
+
+	mu     sync.Mutex
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// NewRedisClient creates a new Redis publisher. The connection is opened lazily.
+func NewRedisClient(config RedisConfig) *RedisClient {
+	if config.KeyPrefix == "" {
+		config.KeyPrefix = "weebcast:"
+	}
+	if config.Channel == "" {
+		config.Channel = "weebcast:activity"
+	}
+	return &RedisClient{
+		config:  config,
+		timeout: 10 * time.Second,
+	}
+}
+
+// Name identifies the Redis publisher in logs
+func (c *RedisClient) Name() string {
+	return "redis"
+}
+
+// PushActivity stores the payload and announces level changes on the channel
+func (c *RedisClient) PushActivity(ctx context.Context, key string, payload *ActivityPayload) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshaling payload: %w", err)
+	}
+
+	// SET ... GET returns the previous value atomically (Redis 6.2+), so level
+	// changes are detected correctly across operator restarts
+	reply, err := c.Do(ctx, "SET", c.config.KeyPrefix+key, string(data), "GET")
+	if err != nil {
+		return fmt.Errorf("setting %s: %w", key, err)
+	}
+
+	var previous ActivityPayload
+	if old, ok := reply.(string); ok {
+		if err := json.Unmarshal([]byte(old), &previous); err != nil {
+			previous = ActivityPayload{}
+		}
+	}
+
+	if previous.ActivityLevel == payload.ActivityLevel {
+		return nil
+	}
+
+	changedAt := payload.LastUpdated
+	if payload.LastActivityChange != nil {
+		changedAt = *payload.LastActivityChange
+	}
+
+	msg, err := json.Marshal(&ActivityChangeMessage{
+		Key:            key,
+		MonitorName:    payload.MonitorName,
+		AnimeID:        payload.AnimeID,
+		AnimeName:      payload.AnimeName,
+		PreviousLevel:  previous.ActivityLevel,
+		ActivityLevel:  payload.ActivityLevel,
+		WeebcastStatus: payload.WeebcastStatus,
+		ChangedAt:      changedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("marshaling change message: %w", err)
+	}
+
+	if _, err := c.Do(ctx, "PUBLISH", c.config.Channel, string(msg)); err != nil {
+		return fmt.Errorf("publishing to %s: %w", c.config.Channel, err)
+	}
+
+	return nil
+}
+
+// Do sends a single command and returns its reply. Replies are decoded as
+// string, int64, nil or []interface{}; Redis error replies are returned as errors.
+func (c *RedisClient) Do(ctx context.Context, args ...string) (interface{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == nil {
+		if err := c.connectLocked(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	reply, err := c.roundTripLocked(ctx, args)
+	if err != nil {
+		var redisErr redisError
+		if !errors.As(err, &redisErr) {
+			// The connection is in an unknown state; reconnect next time
+			c.closeLocked()
+		}
+		return nil, err
+	}
+
+	return reply, nil
+}
+
+// Start waits for the context to be cancelled and then closes the
+// connection. It implements manager.Runnable.
+func (c *RedisClient) Start(ctx context.Context) error {
+	<-ctx.Done()
+	return c.Close()
+}
+
+// Close closes the connection to Redis
+func (c *RedisClient) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closeLocked()
+}
+
+// connectLocked dials Redis and runs AUTH and SELECT
+func (c *RedisClient) connectLocked(ctx context.Context) error {
+	dialer := &net.Dialer{Timeout: c.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", c.config.Addr)
+	if err != nil {
+		return fmt.Errorf("connecting to redis: %w", err)
+	}
+	c.conn = conn
+	c.reader = bufio.NewReader(conn)
+
+	if c.config.Password != "" {
+		args := []string{"AUTH", c.config.Password}
+		if c.config.Username != "" {
+			args = []string{"AUTH", c.config.Username, c.config.Password}
+		}
+		if _, err := c.roundTripLocked(ctx, args); err != nil {
+			c.closeLocked()
+			return fmt.Errorf("authenticating: %w", err)
+		}
+	}
+
+	if c.config.DB != 0 {
+		if _, err := c.roundTripLocked(ctx, []string{"SELECT", strconv.Itoa(c.config.DB)}); err != nil {
+			c.closeLocked()
+			return fmt.Errorf("selecting db %d: %w", c.config.DB, err)
+		}
+	}
+
+	return nil
+}
+
+func (c *RedisClient) closeLocked() error {
+	if c.conn == nil {
+		return nil
+	}
+	err := c.conn.Close()
+	c.conn = nil
+	c.reader = nil
+	return err
+}
+
+// roundTripLocked writes a command as a RESP array and reads one reply
+func (c *RedisClient) roundTripLocked(ctx context.Context, args []string) (interface{}, error) {
+	deadline := time.Now().Add(c.timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	if err := c.conn.SetDeadline(deadline); err != nil {
+		return nil, fmt.Errorf("setting deadline: %w", err)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := io.WriteString(c.conn, b.String()); err != nil {
+		return nil, fmt.Errorf("writing command: %w", err)
+	}
+
+	return readRESP(c.reader)
+}
+
+// redisError is an error reply returned by the server
+type redisError string
+
+func (e redisError) Error() string {
+	return string(e)
+}
+
+// readRESP reads a single RESP reply
+func readRESP(r *bufio.Reader) (interface{}, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, fmt.Errorf("reading reply: %w", err)
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, fmt.Errorf("empty reply")
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, redisError(line[1:])
+	case ':':
+		n, err := strconv.ParseInt(line[1:], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parsing integer reply: %w", err)
+		}
+		return n, nil
+	case '$':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("parsing bulk length: %w", err)
+		}
+		if size < 0 {
+			return nil, nil
+		}
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, fmt.Errorf("reading bulk reply: %w", err)
+		}
+		return string(buf[:size]), nil
+	case '*':
+		count, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("parsing array length: %w", err)
+		}
+		if count < 0 {
+			return nil, nil
+		}
+		items := make([]interface{}, 0, count)
+		for i := 0; i < count; i++ {
+			item, err := readRESP(r)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("unexpected reply type %q", line[0])
+	}
+}
@@ -0,0 +1,178 @@
+package webhook
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"testing"
+	"time"
+)
+
+// These tests run against a real Redis server (6.2+) and are skipped unless
+// REDIS_ADDR is set, e.g.
+//
+//	redis-server --port 6390 &
+//	REDIS_ADDR=localhost:6390 go test ./pkg/webhook -run Redis
+
+// newTestRedisClient returns a client using a key prefix and channel unique
+// to the test, and deletes the test's keys afterwards
+func newTestRedisClient(t *testing.T) *RedisClient {
+	t.Helper()
+
+	addr := os.Getenv("REDIS_ADDR")
+	if addr == "" {
+		t.Skip("REDIS_ADDR not set")
+	}
+
+	prefix := fmt.Sprintf("weebcast-test:%d:", time.Now().UnixNano())
+	client := NewRedisClient(RedisConfig{
+		Addr:      addr,
+		KeyPrefix: prefix,
+		Channel:   prefix + "activity",
+	})
+	t.Cleanup(func() {
+		_, _ = client.Do(context.Background(), "DEL", prefix+"mal-overall")
+		_ = client.Close()
+	})
+	return client
+}
+
+// redisSubscriber receives messages published on a channel
+type redisSubscriber struct {
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// subscribe opens a separate connection subscribed to the client's channel
+func subscribe(t *testing.T, client *RedisClient) *redisSubscriber {
+	t.Helper()
+
+	conn, err := net.Dial("tcp", client.config.Addr)
+	if err != nil {
+		t.Fatalf("connecting subscriber: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	channel := client.config.Channel
+	if _, err := fmt.Fprintf(conn, "*2\r\n$9\r\nSUBSCRIBE\r\n$%d\r\n%s\r\n", len(channel), channel); err != nil {
+		t.Fatalf("subscribing: %v", err)
+	}
+
+	sub := &redisSubscriber{conn: conn, reader: bufio.NewReader(conn)}
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if _, err := readRESP(sub.reader); err != nil {
+		t.Fatalf("reading subscribe confirmation: %v", err)
+	}
+	return sub
+}
+
+// next returns the next message on the channel, or false if none arrives
+// within the timeout
+func (s *redisSubscriber) next(t *testing.T, timeout time.Duration) (*ActivityChangeMessage, bool) {
+	t.Helper()
+
+	_ = s.conn.SetReadDeadline(time.Now().Add(timeout))
+	reply, err := readRESP(s.reader)
+	if err != nil {
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return nil, false
+		}
+		t.Fatalf("reading message: %v", err)
+	}
+
+	items, ok := reply.([]interface{})
+	if !ok || len(items) != 3 || items[0] != "message" {
+		t.Fatalf("unexpected push reply %#v", reply)
+	}
+
+	var msg ActivityChangeMessage
+	if err := json.Unmarshal([]byte(items[2].(string)), &msg); err != nil {
+		t.Fatalf("decoding message: %v", err)
+	}
+	return &msg, true
+}
+
+func testRedisPayload(level string) *ActivityPayload {
+	return &ActivityPayload{
+		MonitorName:    "mal-overall",
+		ActivityLevel:  level,
+		WeebcastStatus: "Forecast: " + level,
+		LastUpdated:    time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestRedisPushActivityStoresPayload(t *testing.T) {
+	ctx := context.Background()
+	client := newTestRedisClient(t)
+
+	payload := testRedisPayload("Medium")
+	if err := client.PushActivity(ctx, "mal-overall", payload); err != nil {
+		t.Fatalf("PushActivity: %v", err)
+	}
+
+	reply, err := client.Do(ctx, "GET", client.config.KeyPrefix+"mal-overall")
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	stored, ok := reply.(string)
+	if !ok {
+		t.Fatalf("GET returned %#v, want a string", reply)
+	}
+
+	var got ActivityPayload
+	if err := json.Unmarshal([]byte(stored), &got); err != nil {
+		t.Fatalf("decoding stored payload: %v", err)
+	}
+	if got.MonitorName != payload.MonitorName || got.ActivityLevel != payload.ActivityLevel ||
+		got.WeebcastStatus != payload.WeebcastStatus || !got.LastUpdated.Equal(payload.LastUpdated) {
+		t.Errorf("stored payload = %+v, want %+v", got, *payload)
+	}
+}
+
+func TestRedisPushActivityPublishesLevelChanges(t *testing.T) {
+	ctx := context.Background()
+	client := newTestRedisClient(t)
+	sub := subscribe(t, client)
+
+	if err := client.PushActivity(ctx, "mal-overall", testRedisPayload("Low")); err != nil {
+		t.Fatalf("PushActivity: %v", err)
+	}
+	if msg, ok := sub.next(t, 5*time.Second); !ok || msg.ActivityLevel != "Low" || msg.PreviousLevel != "" {
+		t.Fatalf("first push published %+v, want a change to Low", msg)
+	}
+
+	// A level change publishes exactly one message, dated by the change
+	// rather than the poll
+	changed := time.Date(2026, 10, 18, 11, 50, 0, 0, time.UTC)
+	high := testRedisPayload("High")
+	high.LastActivityChange = &changed
+	if err := client.PushActivity(ctx, "mal-overall", high); err != nil {
+		t.Fatalf("PushActivity: %v", err)
+	}
+	msg, ok := sub.next(t, 5*time.Second)
+	if !ok {
+		t.Fatal("level change published nothing")
+	}
+	if msg.Key != "mal-overall" || msg.PreviousLevel != "Low" || msg.ActivityLevel != "High" {
+		t.Errorf("change message = %+v, want mal-overall Low -> High", msg)
+	}
+	if !msg.ChangedAt.Equal(changed) {
+		t.Errorf("changedAt = %s, want %s", msg.ChangedAt, changed)
+	}
+	if extra, ok := sub.next(t, 500*time.Millisecond); ok {
+		t.Errorf("level change published a second message %+v", extra)
+	}
+
+	// An unchanged level publishes nothing
+	if err := client.PushActivity(ctx, "mal-overall", testRedisPayload("High")); err != nil {
+		t.Fatalf("PushActivity: %v", err)
+	}
+	if extra, ok := sub.next(t, 500*time.Millisecond); ok {
+		t.Errorf("unchanged level published %+v", extra)
+	}
+}
@@ -0,0 +1,373 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// S3Config configures an S3-compatible object storage publisher
+type S3Config struct {
+	// Endpoint is the storage endpoint, e.g. https://s3.amazonaws.com or http://minio:9000
+	Endpoint string
+	// Region is used for request signing
+	Region string
+	// Bucket receives the published documents
+	Bucket string
+	// Prefix is prepended to every object key, e.g. "api/"
+	Prefix string
+	// PathStyle addresses the bucket in the path rather than the host name (needed for MinIO)
+	PathStyle bool
+	// ContentType is set on every object
+	ContentType string
+	// CacheControl is set on every object when not empty
+	CacheControl string
+	// AccessKeyID and SecretAccessKey are the signing credentials
+	AccessKeyID     string
+	SecretAccessKey string
+}
+
+// S3Client publishes activity payloads as JSON objects to an S3-compatible bucket.
+// Each monitor is written to <prefix><key>.json and an index of all published
+// monitors is kept at <prefix>index.json. The index is reloaded from the
+// bucket on first use, so monitors published before a restart stay listed
+// until they are deleted.
+type S3Client struct {
+	httpClient *http.Client
+	config     S3Config
+	endpoint   *url.URL
+
+	mu       sync.Mutex
+	payloads map[string]*ActivityPayload
+	loaded   bool
+}
+
+// NewS3Client creates a new S3-compatible storage client
+func NewS3Client(config S3Config) (*S3Client, error) {
+	endpoint, err := url.Parse(strings.TrimSuffix(config.Endpoint, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("parsing endpoint: %w", err)
+	}
+	if endpoint.Scheme == "" || endpoint.Host == "" {
+		return nil, fmt.Errorf("endpoint %q must include scheme and host", config.Endpoint)
+	}
+	if config.Bucket == "" {
+		return nil, fmt.Errorf("bucket is required")
+	}
+	if config.Region == "" {
+		config.Region = "us-east-1"
+	}
+	if config.ContentType == "" {
+		config.ContentType = "application/json"
+	}
+
+	return &S3Client{
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		config:   config,
+		endpoint: endpoint,
+		payloads: make(map[string]*ActivityPayload),
+	}, nil
+}
+
+// Name identifies the S3 publisher in logs
+func (c *S3Client) Name() string {
+	return "s3"
+}
+
+// PushActivity writes the payload and refreshes the index document
+func (c *S3Client) PushActivity(ctx context.Context, key string, payload *ActivityPayload) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshaling payload: %w", err)
+	}
+
+	if err := c.PutDocument(ctx, key+".json", c.config.ContentType, data); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	if err := c.loadIndexLocked(ctx); err != nil {
+		c.mu.Unlock()
+		return err
+	}
+	c.payloads[key] = payload
+	index := c.indexLocked()
+	c.mu.Unlock()
+
+	return c.putIndex(ctx, index)
+}
+
+// RemoveActivity deletes a monitor's object and drops it from the index
+func (c *S3Client) RemoveActivity(ctx context.Context, key string) error {
+	c.mu.Lock()
+	if err := c.loadIndexLocked(ctx); err != nil {
+		c.mu.Unlock()
+		return err
+	}
+	delete(c.payloads, key)
+	index := c.indexLocked()
+	c.mu.Unlock()
+
+	if err := c.DeleteDocument(ctx, key+".json"); err != nil {
+		return err
+	}
+
+	return c.putIndex(ctx, index)
+}
+
+// putIndex uploads the index document
+func (c *S3Client) putIndex(ctx context.Context, index map[string][]*ActivityPayload) error {
+	data, err := json.Marshal(index)
+	if err != nil {
+		return fmt.Errorf("marshaling index: %w", err)
+	}
+
+	return c.PutDocument(ctx, "index.json", c.config.ContentType, data)
+}
+
+// loadIndexLocked seeds the payloads from the index in the bucket the first
+// time it is called
+func (c *S3Client) loadIndexLocked(ctx context.Context) error {
+	if c.loaded {
+		return nil
+	}
+
+	resp, err := c.do(ctx, http.MethodGet, "index.json", "", nil)
+	if err != nil {
+		return fmt.Errorf("loading index: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		var index struct {
+			Monitors []*ActivityPayload `json:"monitors"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&index); err != nil {
+			return fmt.Errorf("decoding index: %w", err)
+		}
+		for _, payload := range index.Monitors {
+			c.payloads[payloadKey(payload)] = payload
+		}
+	case http.StatusNotFound:
+		// Nothing published yet
+	default:
+		return fmt.Errorf("loading index: unexpected status: %d", resp.StatusCode)
+	}
+
+	c.loaded = true
+	return nil
+}
+
+// indexLocked builds the index document in the same shape as /api/activity/all
+func (c *S3Client) indexLocked() map[string][]*ActivityPayload {
+	keys := make([]string, 0, len(c.payloads))
+	for key := range c.payloads {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	monitors := make([]*ActivityPayload, 0, len(keys))
+	for _, key := range keys {
+		monitors = append(monitors, c.payloads[key])
+	}
+	return map[string][]*ActivityPayload{"monitors": monitors}
+}
+
+// PutDocument uploads a single object below the configured prefix
+func (c *S3Client) PutDocument(ctx context.Context, name, contentType string, data []byte) error {
+	resp, err := c.do(ctx, http.MethodPut, name, contentType, data)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// GetDocument downloads a single object below the configured prefix
+func (c *S3Client) GetDocument(ctx context.Context, name string) ([]byte, error) {
+	resp, err := c.do(ctx, http.MethodGet, name, "", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", name, err)
+		}
+		return data, nil
+	case http.StatusNotFound:
+		return nil, ErrDocumentNotFound
+	default:
+		return nil, fmt.Errorf("unexpected status: %d", resp.StatusCode)
+	}
+}
+
+// DeleteDocument deletes a single object below the configured prefix
+func (c *S3Client) DeleteDocument(ctx context.Context, name string) error {
+	resp, err := c.do(ctx, http.MethodDelete, name, "", nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// S3 answers 204 whether or not the object existed
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status deleting %s: %d", name, resp.StatusCode)
+	}
+
+	return nil
+}
+
+// do sends a signed request for an object below the configured prefix
+func (c *S3Client) do(ctx context.Context, method, name, contentType string, data []byte) (*http.Response, error) {
+	objectURL := c.objectURL(c.config.Prefix + name)
+
+	req, err := http.NewRequestWithContext(ctx, method, objectURL.String(), bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	if method == http.MethodPut {
+		req.Header.Set("Content-Type", contentType)
+		if c.config.CacheControl != "" {
+			req.Header.Set("Cache-Control", c.config.CacheControl)
+		}
+	}
+	c.sign(req, data, time.Now().UTC())
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("executing request: %w", err)
+	}
+	return resp, nil
+}
+
+// objectURL returns the URL of an object using path-style or virtual-hosted addressing
+func (c *S3Client) objectURL(objectKey string) *url.URL {
+	u := *c.endpoint
+	if c.config.PathStyle {
+		u.Path = u.Path + "/" + c.config.Bucket + "/" + objectKey
+	} else {
+		u.Host = c.config.Bucket + "." + u.Host
+		u.Path = u.Path + "/" + objectKey
+	}
+	u.RawPath = s3EscapePath(u.Path)
+	return &u
+}
+
+// sign adds AWS Signature Version 4 headers to the request
+func (c *S3Client) sign(req *http.Request, body []byte, now time.Time) {
+	payloadHash := sha256Hex(body)
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		s3CanonicalQuery(req.URL.Query()),
+		"host:" + req.URL.Host,
+		"x-amz-content-sha256:" + payloadHash,
+		"x-amz-date:" + amzDate,
+		"",
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := fmt.Sprintf("%s/%s/s3/aws4_request", date, c.config.Region)
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+c.config.SecretAccessKey), date)
+	key = hmacSHA256(key, c.config.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		c.config.AccessKeyID, scope, signedHeaders, signature,
+	))
+}
+
+// s3EscapePath URI-encodes each path segment as required by SigV4, leaving
+// only unreserved characters unescaped
+func s3EscapePath(path string) string {
+	var b strings.Builder
+	for i := 0; i < len(path); i++ {
+		ch := path[i]
+		switch {
+		case ch >= 'A' && ch <= 'Z', ch >= 'a' && ch <= 'z', ch >= '0' && ch <= '9',
+			ch == '-', ch == '_', ch == '.', ch == '~', ch == '/':
+			b.WriteByte(ch)
+		default:
+			fmt.Fprintf(&b, "%%%02X", ch)
+		}
+	}
+	return b.String()
+}
+
+// s3CanonicalQuery encodes query parameters sorted by name and value, as
+// required by SigV4
+func s3CanonicalQuery(query url.Values) string {
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var pairs []string
+	for _, name := range names {
+		values := append([]string(nil), query[name]...)
+		sort.Strings(values)
+		for _, value := range values {
+			pairs = append(pairs, s3EscapeQuery(name)+"="+s3EscapeQuery(value))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// s3EscapeQuery URI-encodes a query name or value, slashes included
+func s3EscapeQuery(value string) string {
+	return strings.ReplaceAll(s3EscapePath(value), "/", "%2F")
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
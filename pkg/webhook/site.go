@@ -0,0 +1,249 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/weebcast/weebcast-operator/pkg/mal"
+)
+
+// SiteDocuments renders the worker API surface for a set of published
+// payloads keyed by activity key. The result maps each route (e.g.
+// /api/activity, /api/anime/16498) to the JSON document the worker would
+// return for it.
+func SiteDocuments(payloads map[string]*ActivityPayload, now time.Time) map[string]interface{} {
+	keys := make([]string, 0, len(payloads))
+	for key := range payloads {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	monitors := make([]*ActivityPayload, 0, len(keys))
+	for _, key := range keys {
+		monitors = append(monitors, payloads[key])
+	}
+
+	docs := map[string]interface{}{
+		"/api/activity/all": map[string]interface{}{"monitors": monitors},
+	}
+
+	overall := payloads[OverallActivityKey]
+	if overall == nil {
+		docs["/api/activity"] = map[string]interface{}{
+			"activityLevel":  "Unknown",
+			"weebcastStatus": "No data available yet",
+			"lastUpdated":    nil,
+		}
+		docs["/api/trending"] = map[string]interface{}{"trending": []TrendingItem{}}
+		docs["/api/seasonal"] = map[string]interface{}{
+			"seasonal": []TrendingItem{},
+			"season":   mal.CurrentSeason(now),
+		}
+	} else {
+		trending := overall.TrendingAnime
+		if trending == nil {
+			trending = []TrendingItem{}
+		}
+
+		// Fall back to trending anime like the worker does for older payloads
+		seasonal, seasonalUpdated := overall.SeasonalAnime, overall.SeasonalUpdated
+		if seasonal == nil {
+			seasonal, seasonalUpdated = trending, overall.TrendingUpdated
+		}
+
+		season := overall.CurrentSeason
+		if season == "" {
+			season = mal.CurrentSeason(now)
+		}
+
+		docs["/api/activity"] = overall
+		docs["/api/trending"] = map[string]interface{}{
+			"trending": trending,
+			"updated":  overall.TrendingUpdated,
+		}
+		docs["/api/seasonal"] = map[string]interface{}{
+			"seasonal": seasonal,
+			"season":   season,
+			"updated":  seasonalUpdated,
+		}
+	}
+
+	for _, payload := range monitors {
+		if payload.AnimeID > 0 {
+			docs[fmt.Sprintf("/api/anime/%d", payload.AnimeID)] = payload
+		}
+	}
+
+	return docs
+}
+
+// StaticSiteWriter renders the worker API as static JSON files in a
+// directory, so any static file server can host the site without Cloudflare.
+// Each route is written to <dir><route>/index.json; configure the file
+// server to use index.json as the directory index. Monitors already in the
+// directory's /api/activity/all are reloaded on first use, so they stay
+// listed across restarts until they are deleted.
+type StaticSiteWriter struct {
+	dir string
+
+	mu       sync.Mutex
+	payloads map[string]*ActivityPayload
+	loaded   bool
+}
+
+// NewStaticSiteWriter creates a new static site writer rooted at dir
+func NewStaticSiteWriter(dir string) *StaticSiteWriter {
+	return &StaticSiteWriter{
+		dir:      dir,
+		payloads: make(map[string]*ActivityPayload),
+	}
+}
+
+// Name identifies the static site publisher in logs
+func (w *StaticSiteWriter) Name() string {
+	return "static"
+}
+
+// PushActivity records the payload and re-renders every route
+func (w *StaticSiteWriter) PushActivity(ctx context.Context, key string, payload *ActivityPayload) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := w.loadLocked(); err != nil {
+		return err
+	}
+	w.payloads[key] = payload
+
+	return w.renderLocked()
+}
+
+// RemoveActivity drops a monitor from every route and deletes its anime route
+func (w *StaticSiteWriter) RemoveActivity(_ context.Context, key string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := w.loadLocked(); err != nil {
+		return err
+	}
+	payload, ok := w.payloads[key]
+	if !ok {
+		return nil
+	}
+	delete(w.payloads, key)
+
+	if payload.AnimeID > 0 {
+		route := filepath.FromSlash(fmt.Sprintf("/api/anime/%d", payload.AnimeID))
+		if err := os.Remove(filepath.Join(w.dir, route, "index.json")); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("removing %s: %w", route, err)
+		}
+		// Leave the directory if something else was written into it
+		_ = os.Remove(filepath.Join(w.dir, route))
+	}
+
+	return w.renderLocked()
+}
+
+// loadLocked seeds the payloads from the rendered /api/activity/all the
+// first time it is called
+func (w *StaticSiteWriter) loadLocked() error {
+	if w.loaded {
+		return nil
+	}
+
+	data, err := os.ReadFile(filepath.Join(w.dir, "api", "activity", "all", "index.json"))
+	switch {
+	case os.IsNotExist(err):
+		// Nothing rendered yet
+	case err != nil:
+		return fmt.Errorf("reading monitor index: %w", err)
+	default:
+		var index struct {
+			Monitors []*ActivityPayload `json:"monitors"`
+		}
+		if err := json.Unmarshal(data, &index); err != nil {
+			return fmt.Errorf("decoding monitor index: %w", err)
+		}
+		for _, payload := range index.Monitors {
+			w.payloads[payloadKey(payload)] = payload
+		}
+	}
+
+	w.loaded = true
+	return nil
+}
+
+// renderLocked writes every route for the current payloads
+func (w *StaticSiteWriter) renderLocked() error {
+	for route, doc := range SiteDocuments(w.payloads, time.Now()) {
+		data, err := json.Marshal(doc)
+		if err != nil {
+			return fmt.Errorf("marshaling %s: %w", route, err)
+		}
+		if err := w.writeFile(filepath.Join(filepath.FromSlash(route), "index.json"), data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PutDocument writes a file below the site directory. The content type is
+// left to the file server, which infers it from the file extension.
+func (w *StaticSiteWriter) PutDocument(_ context.Context, path, _ string, data []byte) error {
+	return w.writeFile(filepath.FromSlash(path), data)
+}
+
+// GetDocument reads a file below the site directory
+func (w *StaticSiteWriter) GetDocument(_ context.Context, path string) ([]byte, error) {
+	data, err := os.ReadFile(filepath.Join(w.dir, filepath.FromSlash(path)))
+	if os.IsNotExist(err) {
+		return nil, ErrDocumentNotFound
+	}
+	return data, err
+}
+
+// DeleteDocument removes a file below the site directory
+func (w *StaticSiteWriter) DeleteDocument(_ context.Context, path string) error {
+	if err := os.Remove(filepath.Join(w.dir, filepath.FromSlash(path))); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("removing %s: %w", path, err)
+	}
+	return nil
+}
+
+// writeFile atomically replaces a file below the site directory so readers
+// never observe a partially written document
+func (w *StaticSiteWriter) writeFile(name string, data []byte) error {
+	path := filepath.Join(w.dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("creating directory for %s: %w", name, err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("creating temp file for %s: %w", name, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing %s: %w", name, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("closing %s: %w", name, err)
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return fmt.Errorf("setting permissions on %s: %w", name, err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("renaming %s: %w", name, err)
+	}
+
+	return nil
+}
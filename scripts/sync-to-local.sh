@@ -60,8 +60,16 @@ echo "$monitors" | jq -c '.items[]' | while read -r item; do
         trendingAnime: (.status.trendingAnime // []),
         seasonalAnime: (.status.seasonalAnime // []),
         currentSeason: (.status.currentSeason // null),
-        lastUpdated: .status.lastChecked
-    }')
+        lastUpdated: .status.lastChecked,
+        lastActivityChange: .status.lastActivityChange,
+        trendingUpdated: .status.trendingAnimeUpdated,
+        seasonalUpdated: .status.seasonalAnimeUpdated,
+        broadcast: .status.broadcast,
+        level: (if .status.customLevel then {name: .status.customLevel, icon: .status.customLevelIcon} else null end),
+        localizedStatus: .status.localizedStatus,
+        forecast: .status.forecast,
+        outlook: .status.outlook
+    } | with_entries(select(.value != null))')
     
     # Send to local API
     response=$(curl -s -X POST "$API_URL/api/sync" \
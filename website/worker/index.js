@@ -140,7 +140,10 @@ async function handleGetTrending(env, corsHeaders) {
       });
     }
 
-    return new Response(JSON.stringify({ trending: data.trendingAnime }), {
+    return new Response(JSON.stringify({
+      trending: data.trendingAnime,
+      updated: data.trendingUpdated || null
+    }), {
       headers: { ...corsHeaders, 'Content-Type': 'application/json' }
     });
   } catch (error) {
@@ -166,7 +169,8 @@ async function handleGetSeasonal(env, corsHeaders) {
 
     return new Response(JSON.stringify({ 
       seasonal: seasonalList,
-      season: data.currentSeason || getCurrentSeason()
+      season: data.currentSeason || getCurrentSeason(),
+      updated: (data.seasonalAnime ? data.seasonalUpdated : data.trendingUpdated) || null
     }), {
       headers: { ...corsHeaders, 'Content-Type': 'application/json' }
     });
@@ -195,21 +199,14 @@ function getCurrentSeason() {
 // Handle sync from operator (for local development)
 async function handleSync(request, env, corsHeaders) {
   try {
-    const payload = await request.json();
-    const key = payload.key || 'mal-overall';
+    const { key: requestedKey, ...activity } = await request.json();
+    const key = requestedKey || 'mal-overall';
     
-    // Store the activity data
+    // Store the whole payload as the operator publishes it to KV, so new
+    // payload fields need no change here
     await env.WEEBCAST_KV.put(key, JSON.stringify({
-      monitorName: payload.monitorName,
-      animeId: payload.animeId,
-      animeName: payload.animeName,
-      activityLevel: payload.activityLevel,
-      weebcastStatus: payload.weebcastStatus,
-      metrics: payload.metrics,
-      trendingAnime: payload.trendingAnime,
-      seasonalAnime: payload.seasonalAnime,
-      currentSeason: payload.currentSeason,
-      lastUpdated: payload.lastUpdated || new Date().toISOString()
+      ...activity,
+      lastUpdated: activity.lastUpdated || new Date().toISOString()
     }));
 
     return new Response(JSON.stringify({ success: true, key }), {